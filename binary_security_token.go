@@ -2,6 +2,7 @@ package xmlsecurity
 
 import (
 	"crypto/x509"
+	"encoding/asn1"
 	"encoding/base64"
 	"errors"
 
@@ -9,9 +10,16 @@ import (
 	"github.com/deb-ict/go-xml"
 )
 
+const (
+	X509v3ValueType        string = "http://docs.oasis-open.org/wss/2004/01/oasis-200401-wss-x509-token-profile-1.0#X509v3"
+	X509PKIPathv1ValueType string = "http://docs.oasis-open.org/wss/2004/01/oasis-200401-wss-x509-token-profile-1.0#X509PKIPathv1"
+	PKCS7ValueType         string = "http://docs.oasis-open.org/wss/2004/01/oasis-200401-wss-x509-token-profile-1.0#PKCS7"
+)
+
 type BinarySecurityToken interface {
 	xml.Node
 	X509CertificateProvider
+	X509ChainProvider
 	GetId() string
 	SetId(id string)
 	GetValueType() string
@@ -33,6 +41,53 @@ func NewBinarySecurityToken(context xml.Context) (BinarySecurityToken, error) {
 	return &binarySecurityToken{}, nil
 }
 
+func NewX509BinarySecurityToken(context xml.Context, cert *x509.Certificate) (BinarySecurityToken, error) {
+	if cert == nil {
+		return nil, errors.New("certificate is nil")
+	}
+	id, err := GetIdGenerator(context).GenerateId(context, "BinarySecurityToken")
+	if err != nil {
+		return nil, err
+	}
+
+	return &binarySecurityToken{
+		Id:           id,
+		ValueType:    X509v3ValueType,
+		EncodingType: Base64BinaryEncodingType,
+		Value:        base64.StdEncoding.EncodeToString(cert.Raw),
+	}, nil
+}
+
+func NewX509ChainBinarySecurityToken(context xml.Context, chain []*x509.Certificate) (BinarySecurityToken, error) {
+	if len(chain) == 0 {
+		return nil, ErrCertificateChainEmpty
+	}
+	id, err := GetIdGenerator(context).GenerateId(context, "BinarySecurityToken")
+	if err != nil {
+		return nil, err
+	}
+
+	// A PkiPath is ordered from the trust anchor to the end entity
+	path := make([]asn1.RawValue, len(chain))
+	for i, cert := range chain {
+		if cert == nil {
+			return nil, errors.New("certificate is nil")
+		}
+		path[len(chain)-1-i] = asn1.RawValue{FullBytes: cert.Raw}
+	}
+	value, err := asn1.Marshal(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &binarySecurityToken{
+		Id:           id,
+		ValueType:    X509PKIPathv1ValueType,
+		EncodingType: Base64BinaryEncodingType,
+		Value:        base64.StdEncoding.EncodeToString(value),
+	}, nil
+}
+
 func NewBinarySecurityTokenNode(context xml.Context) (xml.Node, error) {
 	return NewBinarySecurityToken(context)
 }
@@ -70,19 +125,40 @@ func (node *binarySecurityToken) SetValue(value string) {
 }
 
 func (node *binarySecurityToken) GetX509Certificate(context xml.Context) (*x509.Certificate, error) {
-	if node.GetValueType() != "http://docs.oasis-open.org/wss/2004/01/oasis-200401-wss-x509-token-profile-1.0#X509v3" {
+	chain, err := node.GetX509CertificateChain(context)
+	if err != nil {
+		return nil, err
+	}
+	return chain[0], nil
+}
+
+func (node *binarySecurityToken) GetX509CertificateChain(context xml.Context) ([]*x509.Certificate, error) {
+	switch node.GetValueType() {
+	case X509v3ValueType, X509PKIPathv1ValueType, PKCS7ValueType:
+	default:
 		return nil, errors.New("invalid ValueType")
 	}
-	if node.EncodingType != "http://docs.oasis-open.org/wss/2004/01/oasis-200401-wss-soap-message-security-1.0#Base64Binary" {
+	if node.EncodingType != Base64BinaryEncodingType {
 		return nil, errors.New("invalid EncodingType")
 	}
 
-	certificateBytes, err := base64.StdEncoding.DecodeString(node.GetValue())
+	data, err := base64.StdEncoding.DecodeString(node.GetValue())
 	if err != nil {
 		return nil, err
 	}
 
-	return x509.ParseCertificate(certificateBytes)
+	switch node.GetValueType() {
+	case X509PKIPathv1ValueType:
+		return parsePkiPath(data)
+	case PKCS7ValueType:
+		return parsePkcs7Certificates(data)
+	default:
+		certificate, err := x509.ParseCertificate(data)
+		if err != nil {
+			return nil, err
+		}
+		return []*x509.Certificate{certificate}, nil
+	}
 }
 
 func (node *binarySecurityToken) LoadXml(context xml.Context, el *etree.Element) error {
@@ -100,6 +176,11 @@ func (node *binarySecurityToken) LoadXml(context xml.Context, el *etree.Element)
 }
 
 func (node *binarySecurityToken) GetXml(context xml.Context) (*etree.Element, error) {
+	err := generateMissingId(context, node, "BinarySecurityToken")
+	if err != nil {
+		return nil, err
+	}
+
 	el := etree.NewElement("BinarySecurityToken")
 	el.Space = context.GetNamespacePrefix(WsseNamespace)
 
@@ -1,6 +1,10 @@
 package xmlsecurity
 
 import (
+	"bytes"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
 	"fmt"
 	"testing"
 
@@ -173,3 +177,181 @@ func Test_BinarySecurityToken_GetXml(t *testing.T) {
 		t.Fatalf("BinarySecurityToken XML = %s; want %s", resultXml, testCaseXml)
 	}
 }
+
+func Test_NewX509BinarySecurityToken(t *testing.T) {
+	certificate := newTestCertificate(t, "Test", nil, nil).Certificate
+
+	// Prepare the test case
+	testCaseDocument := etree.NewDocument()
+	testCaseContext := xml.NewContext(testCaseDocument)
+	ConfigureContext(testCaseContext)
+
+	// Create test case BinarySecurityToken
+	testCaseBinarySecurityToken, err := NewX509BinarySecurityToken(testCaseContext, certificate)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Validate test case BinarySecurityToken
+	if testCaseBinarySecurityToken.GetId() == "" {
+		t.Fatal("BinarySecurityToken.Id is empty")
+	}
+	if testCaseBinarySecurityToken.GetValueType() != X509v3ValueType {
+		t.Fatalf("BinarySecurityToken.ValueType = %s; want %s", testCaseBinarySecurityToken.GetValueType(), X509v3ValueType)
+	}
+	if testCaseBinarySecurityToken.GetEncodingType() != Base64BinaryEncodingType {
+		t.Fatalf("BinarySecurityToken.EncodingType = %s; want %s", testCaseBinarySecurityToken.GetEncodingType(), Base64BinaryEncodingType)
+	}
+
+	// Round trip the test case BinarySecurityToken
+	testCaseBinarySecurityTokenElement, err := testCaseBinarySecurityToken.GetXml(testCaseContext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCaseBinarySecurityTokenElement.CreateAttr("xmlns:wsse", WsseNamespace)
+	testCaseBinarySecurityTokenElement.CreateAttr("xmlns:wsu", WsuNamespace)
+	testCaseDocument.SetRoot(testCaseBinarySecurityTokenElement)
+
+	loadedBinarySecurityToken, err := NewBinarySecurityToken(testCaseContext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = loadedBinarySecurityToken.LoadXml(testCaseContext, testCaseDocument.Root())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loadedBinarySecurityToken.GetId() != testCaseBinarySecurityToken.GetId() {
+		t.Fatalf("BinarySecurityToken.Id = %s; want %s", loadedBinarySecurityToken.GetId(), testCaseBinarySecurityToken.GetId())
+	}
+	result, err := loadedBinarySecurityToken.GetX509Certificate(testCaseContext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Equal(certificate) {
+		t.Fatal("BinarySecurityToken.GetX509Certificate() returned a different certificate")
+	}
+}
+
+func Test_NewX509ChainBinarySecurityToken(t *testing.T) {
+	root := newTestCertificate(t, "Test Root", nil, nil)
+	leaf := newTestCertificate(t, "Test Leaf", root, nil)
+
+	// Create test case BinarySecurityToken
+	testCaseBinarySecurityToken, err := NewX509ChainBinarySecurityToken(nil, []*x509.Certificate{leaf.Certificate, root.Certificate})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if testCaseBinarySecurityToken.GetValueType() != X509PKIPathv1ValueType {
+		t.Fatalf("BinarySecurityToken.ValueType = %s; want %s", testCaseBinarySecurityToken.GetValueType(), X509PKIPathv1ValueType)
+	}
+
+	// Validate the PkiPath order
+	value, err := base64.StdEncoding.DecodeString(testCaseBinarySecurityToken.GetValue())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var path []asn1.RawValue
+	_, err = asn1.Unmarshal(value, &path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(path) != 2 || !bytes.Equal(path[0].FullBytes, root.Certificate.Raw) || !bytes.Equal(path[1].FullBytes, leaf.Certificate.Raw) {
+		t.Fatal("PkiPath is not ordered from trust anchor to end entity")
+	}
+
+	_, err = NewX509ChainBinarySecurityToken(nil, nil)
+	if err != ErrCertificateChainEmpty {
+		t.Fatalf("NewX509ChainBinarySecurityToken() = %v; want %v", err, ErrCertificateChainEmpty)
+	}
+}
+
+func Test_BinarySecurityToken_GetX509CertificateChain_PKIPath(t *testing.T) {
+	root := newTestCertificate(t, "Test Root", nil, nil)
+	leaf := newTestCertificate(t, "Test Leaf", root, nil)
+
+	// Create test case BinarySecurityToken
+	testCaseBinarySecurityToken, err := NewX509ChainBinarySecurityToken(nil, []*x509.Certificate{leaf.Certificate, root.Certificate})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Validate the test case chain
+	chain, err := testCaseBinarySecurityToken.GetX509CertificateChain(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(chain) != 2 || !chain[0].Equal(leaf.Certificate) || !chain[1].Equal(root.Certificate) {
+		t.Fatal("BinarySecurityToken.GetX509CertificateChain() is not ordered leaf to root")
+	}
+	certificate, err := testCaseBinarySecurityToken.GetX509Certificate(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !certificate.Equal(leaf.Certificate) {
+		t.Fatal("BinarySecurityToken.GetX509Certificate() did not return the leaf certificate")
+	}
+}
+
+func Test_BinarySecurityToken_GetX509CertificateChain_PKCS7(t *testing.T) {
+	root := newTestCertificate(t, "Test Root", nil, nil)
+	intermediate := newTestCertificate(t, "Test Intermediate", root, func(template *x509.Certificate) {
+		template.IsCA = true
+		template.KeyUsage |= x509.KeyUsageCertSign
+	})
+	leaf := newTestCertificate(t, "Test Leaf", intermediate, nil)
+
+	// Create a degenerate PKCS7 signed data structure in set order
+	certificates := append(append(append([]byte{}, root.Certificate.Raw...), leaf.Certificate.Raw...), intermediate.Certificate.Raw...)
+	signedData, err := asn1.Marshal(pkcs7SignedData{
+		Version:          1,
+		DigestAlgorithms: asn1.RawValue{Class: asn1.ClassUniversal, Tag: asn1.TagSet, IsCompound: true},
+		ContentInfo:      asn1.RawValue{FullBytes: []byte{0x30, 0x0b, 0x06, 0x09, 0x2a, 0x86, 0x48, 0x86, 0xf7, 0x0d, 0x01, 0x07, 0x01}},
+		Certificates:     asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: certificates},
+		SignerInfos:      asn1.RawValue{Class: asn1.ClassUniversal, Tag: asn1.TagSet, IsCompound: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	contentInfo, err := asn1.Marshal(struct {
+		ContentType asn1.ObjectIdentifier
+		Content     asn1.RawValue
+	}{
+		ContentType: oidPkcs7SignedData,
+		Content:     asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: signedData},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Create test case BinarySecurityToken
+	testCaseBinarySecurityToken, err := NewBinarySecurityToken(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCaseBinarySecurityToken.SetValueType(PKCS7ValueType)
+	testCaseBinarySecurityToken.SetEncodingType(Base64BinaryEncodingType)
+	testCaseBinarySecurityToken.SetValue(base64.StdEncoding.EncodeToString(contentInfo))
+
+	// Validate the test case chain
+	chain, err := testCaseBinarySecurityToken.GetX509CertificateChain(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(chain) != 3 || !chain[0].Equal(leaf.Certificate) || !chain[1].Equal(intermediate.Certificate) || !chain[2].Equal(root.Certificate) {
+		t.Fatal("BinarySecurityToken.GetX509CertificateChain() is not ordered leaf to root")
+	}
+}
+
+func Test_BinarySecurityToken_GetX509Certificate_InvalidValueType(t *testing.T) {
+	testCaseBinarySecurityToken, err := NewBinarySecurityToken(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCaseBinarySecurityToken.SetValueType("unknown")
+	testCaseBinarySecurityToken.SetEncodingType(Base64BinaryEncodingType)
+
+	_, err = testCaseBinarySecurityToken.GetX509Certificate(nil)
+	if err == nil {
+		t.Fatal("BinarySecurityToken.GetX509Certificate() with an unknown ValueType succeeded")
+	}
+}
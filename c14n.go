@@ -0,0 +1,168 @@
+package xmlsecurity
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/beevik/etree"
+)
+
+func NewC14N10Canonicalizer(withComments bool) Canonicalizer {
+	algorithm := C14N10Algorithm
+	if withComments {
+		algorithm = C14N10WithCommentsAlgorithm
+	}
+	return &canonicalizer{
+		algorithm:    algorithm,
+		mode:         c14n10Mode,
+		withComments: withComments,
+	}
+}
+
+func NewC14N11Canonicalizer(withComments bool) Canonicalizer {
+	algorithm := C14N11Algorithm
+	if withComments {
+		algorithm = C14N11WithCommentsAlgorithm
+	}
+	return &canonicalizer{
+		algorithm:    algorithm,
+		mode:         c14n11Mode,
+		withComments: withComments,
+	}
+}
+
+func (c *canonicalizer) inScopeNamespaces(inScope map[string]string, rendered map[string]string) []string {
+	namespaces := make([]string, 0, len(inScope))
+	for prefix, uri := range inScope {
+		if prefix == "xml" || (prefix != "" && uri == "") {
+			continue
+		}
+		if uri != rendered[prefix] {
+			namespaces = append(namespaces, prefix)
+		}
+	}
+	sort.Strings(namespaces)
+	return namespaces
+}
+
+func (c *canonicalizer) inheritedXmlAttrs(el *etree.Element, ancestors []*etree.Element) []etree.Attr {
+	if c.mode == excC14NMode {
+		return nil
+	}
+
+	found := make(map[string]bool)
+	for _, attr := range el.Attr {
+		if attr.Space == "xml" {
+			found[attr.Key] = true
+		}
+	}
+
+	// The nearest ancestor wins for attributes the element does not carry itself
+	inherited := make([]etree.Attr, 0)
+	bases := make([]string, 0)
+	for _, ancestor := range ancestors {
+		for _, attr := range ancestor.Attr {
+			if attr.Space != "xml" {
+				continue
+			}
+			if c.mode == c14n11Mode && attr.Key == "base" {
+				bases = append(bases, attr.Value)
+				continue
+			}
+			if c.mode == c14n11Mode && attr.Key != "lang" && attr.Key != "space" {
+				continue
+			}
+			if found[attr.Key] {
+				continue
+			}
+			found[attr.Key] = true
+			inherited = append(inherited, etree.Attr{Space: "xml", Key: attr.Key, Value: attr.Value})
+		}
+	}
+
+	// C14N 1.1 resolves the element's xml:base against the omitted ancestors
+	if len(bases) > 0 {
+		base := ""
+		for i := len(bases) - 1; i >= 0; i-- {
+			base = joinXmlBase(base, bases[i])
+		}
+		base = joinXmlBase(base, el.SelectAttrValue("xml:base", ""))
+		inherited = append(inherited, etree.Attr{Space: "xml", Key: "base", Value: base})
+	}
+	return inherited
+}
+
+func mergeXmlAttrs(attrs []etree.Attr, inherited []etree.Attr) []etree.Attr {
+	if len(inherited) == 0 {
+		return attrs
+	}
+
+	merged := make([]etree.Attr, len(attrs), len(attrs)+len(inherited))
+	copy(merged, attrs)
+	for _, attr := range inherited {
+		replaced := false
+		for i := range merged {
+			if merged[i].Space == attr.Space && merged[i].Key == attr.Key {
+				merged[i] = attr
+				replaced = true
+			}
+		}
+		if !replaced {
+			merged = append(merged, attr)
+		}
+	}
+	return merged
+}
+
+func joinXmlBase(base string, ref string) string {
+	if base == "" {
+		return ref
+	}
+	if ref == "" {
+		return base
+	}
+
+	refUrl, err := url.Parse(ref)
+	if err != nil || refUrl.Scheme != "" {
+		return ref
+	}
+	baseUrl, err := url.Parse(base)
+	if err != nil {
+		return ref
+	}
+	if baseUrl.IsAbs() {
+		return baseUrl.ResolveReference(refUrl).String()
+	}
+
+	// Relative bases are merged by path, keeping leading parent segments
+	if strings.HasPrefix(ref, "/") {
+		return removeDotSegments(ref)
+	}
+	return removeDotSegments(base[:strings.LastIndex(base, "/")+1] + ref)
+}
+
+func removeDotSegments(path string) string {
+	segments := strings.Split(path, "/")
+	output := make([]string, 0, len(segments))
+	for i, segment := range segments {
+		last := i == len(segments)-1
+		switch segment {
+		case ".":
+		case "..":
+			rooted := len(output) == 1 && output[0] == ""
+			if len(output) > 0 && output[len(output)-1] != ".." && !rooted {
+				output = output[:len(output)-1]
+			} else if !rooted {
+				output = append(output, "..")
+			}
+		default:
+			output = append(output, segment)
+			continue
+		}
+		if last {
+			output = append(output, "")
+		}
+	}
+	return strings.Join(output, "/")
+}
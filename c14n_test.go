@@ -0,0 +1,255 @@
+package xmlsecurity
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/beevik/etree"
+)
+
+// The W3C Canonical XML examples from section 3 of the specification. Example
+// 3.5 and the DTD driven parts of 3.3 and 3.4 are left out because etree does
+// not process DTDs, example 3.6 is stored as UTF-8 and example 3.7 selects an
+// XPath node-set rather than a subtree, the subset rules are covered by
+// Test_C14NCanonicalizer_Canonicalize_Subsets instead.
+func Test_C14NCanonicalizer_W3CExamples(t *testing.T) {
+	// Create test cases
+	testCases := []string{
+		"example-3.1",
+		"example-3.2",
+		"example-3.3",
+		"example-3.4",
+		"example-3.6",
+	}
+	canonicalizers := []struct {
+		canonicalizer Canonicalizer
+		suffix        string
+	}{
+		{
+			canonicalizer: NewC14N10Canonicalizer(false),
+			suffix:        ".c14n",
+		},
+		{
+			canonicalizer: NewC14N10Canonicalizer(true),
+			suffix:        ".c14n-comments",
+		},
+		{
+			canonicalizer: NewC14N11Canonicalizer(false),
+			suffix:        ".c14n",
+		},
+		{
+			canonicalizer: NewC14N11Canonicalizer(true),
+			suffix:        ".c14n-comments",
+		},
+	}
+
+	for _, tc := range testCases {
+		// Prepare the test case
+		testCaseDocument := etree.NewDocument()
+		err := testCaseDocument.ReadFromFile(filepath.Join("testdata", "c14n", tc+".xml"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, c := range canonicalizers {
+			expected, err := os.ReadFile(filepath.Join("testdata", "c14n", tc+c.suffix))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// Canonicalize the test case document
+			result, err := c.canonicalizer.CanonicalizeDocument(testCaseDocument)
+			if err != nil {
+				t.Fatalf("%s: %v", tc, err)
+			}
+			if string(result) != string(expected) {
+				t.Fatalf("%s: %s.CanonicalizeDocument() = %s; want %s", tc, c.canonicalizer.GetAlgorithm(), result, expected)
+			}
+		}
+	}
+}
+
+func Test_C14NCanonicalizer_Canonicalize_Subtree(t *testing.T) {
+	// Create test case XML
+	testCaseXml := `<doc xmlns="urn:doc" xmlns:a="urn:a" xml:lang="en" xml:space="preserve" xml:id="doc" xml:base="http://example.org/dir/">` +
+		`<a:e1 xml:base="sub/"><e2 attr="x"/></a:e1>` +
+		`</doc>`
+
+	// Create test cases
+	testCases := []struct {
+		canonicalizer Canonicalizer
+		expected      string
+	}{
+		{
+			canonicalizer: NewC14N10Canonicalizer(false),
+			expected:      `<a:e1 xmlns="urn:doc" xmlns:a="urn:a" xml:base="sub/" xml:id="doc" xml:lang="en" xml:space="preserve"><e2 attr="x"></e2></a:e1>`,
+		},
+		{
+			canonicalizer: NewC14N11Canonicalizer(false),
+			expected:      `<a:e1 xmlns="urn:doc" xmlns:a="urn:a" xml:base="http://example.org/dir/sub/" xml:lang="en" xml:space="preserve"><e2 attr="x"></e2></a:e1>`,
+		},
+		{
+			canonicalizer: NewExcC14NCanonicalizer(false),
+			expected:      `<a:e1 xmlns:a="urn:a" xml:base="sub/"><e2 xmlns="urn:doc" attr="x"></e2></a:e1>`,
+		},
+	}
+
+	// Prepare the test case
+	testCaseDocument := etree.NewDocument()
+	err := testCaseDocument.ReadFromString(testCaseXml)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range testCases {
+		// Canonicalize the test case element
+		result, err := tc.canonicalizer.Canonicalize(testCaseDocument.FindElement("//e1"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(result) != tc.expected {
+			t.Fatalf("%s.Canonicalize() = %s; want %s", tc.canonicalizer.GetAlgorithm(), result, tc.expected)
+		}
+	}
+}
+
+func Test_C14NCanonicalizer_Canonicalize_Subsets(t *testing.T) {
+	// Create test case XML
+	testCaseXml := `<doc xmlns="urn:doc" xmlns:a="urn:a" xml:lang="en" xml:space="preserve" xml:base="http://example.org/a/">` +
+		`<outer xml:lang="fr" xml:base="b/">` +
+		`<a:middle xmlns="" xml:base="../c/">` +
+		`<inner a:attr="1" xml:base="d"><leaf/></inner>` +
+		`<plain xmlns:b="urn:b"><b:leaf/></plain>` +
+		`</a:middle>` +
+		`</outer>` +
+		`<empty xmlns=""><e1/></empty>` +
+		`</doc>`
+
+	// Create test cases
+	testCases := []struct {
+		path   string
+		c14n10 string
+		c14n11 string
+	}{
+		{
+			path:   "/doc",
+			c14n10: `<doc xmlns="urn:doc" xmlns:a="urn:a" xml:base="http://example.org/a/" xml:lang="en" xml:space="preserve"><outer xml:base="b/" xml:lang="fr"><a:middle xmlns="" xml:base="../c/"><inner xml:base="d" a:attr="1"><leaf></leaf></inner><plain xmlns:b="urn:b"><b:leaf></b:leaf></plain></a:middle></outer><empty xmlns=""><e1></e1></empty></doc>`,
+			c14n11: `<doc xmlns="urn:doc" xmlns:a="urn:a" xml:base="http://example.org/a/" xml:lang="en" xml:space="preserve"><outer xml:base="b/" xml:lang="fr"><a:middle xmlns="" xml:base="../c/"><inner xml:base="d" a:attr="1"><leaf></leaf></inner><plain xmlns:b="urn:b"><b:leaf></b:leaf></plain></a:middle></outer><empty xmlns=""><e1></e1></empty></doc>`,
+		},
+		{
+			path:   "//a:middle",
+			c14n10: `<a:middle xmlns:a="urn:a" xml:base="../c/" xml:lang="fr" xml:space="preserve"><inner xml:base="d" a:attr="1"><leaf></leaf></inner><plain xmlns:b="urn:b"><b:leaf></b:leaf></plain></a:middle>`,
+			c14n11: `<a:middle xmlns:a="urn:a" xml:base="http://example.org/a/c/" xml:lang="fr" xml:space="preserve"><inner xml:base="d" a:attr="1"><leaf></leaf></inner><plain xmlns:b="urn:b"><b:leaf></b:leaf></plain></a:middle>`,
+		},
+		{
+			path:   "//inner",
+			c14n10: `<inner xmlns:a="urn:a" xml:base="d" xml:lang="fr" xml:space="preserve" a:attr="1"><leaf></leaf></inner>`,
+			c14n11: `<inner xmlns:a="urn:a" xml:base="http://example.org/a/c/d" xml:lang="fr" xml:space="preserve" a:attr="1"><leaf></leaf></inner>`,
+		},
+		{
+			path:   "//plain",
+			c14n10: `<plain xmlns:a="urn:a" xmlns:b="urn:b" xml:base="../c/" xml:lang="fr" xml:space="preserve"><b:leaf></b:leaf></plain>`,
+			c14n11: `<plain xmlns:a="urn:a" xmlns:b="urn:b" xml:base="http://example.org/a/c/" xml:lang="fr" xml:space="preserve"><b:leaf></b:leaf></plain>`,
+		},
+		{
+			path:   "//b:leaf",
+			c14n10: `<b:leaf xmlns:a="urn:a" xmlns:b="urn:b" xml:base="../c/" xml:lang="fr" xml:space="preserve"></b:leaf>`,
+			c14n11: `<b:leaf xmlns:a="urn:a" xmlns:b="urn:b" xml:base="http://example.org/a/c/" xml:lang="fr" xml:space="preserve"></b:leaf>`,
+		},
+		{
+			path:   "//empty",
+			c14n10: `<empty xmlns:a="urn:a" xml:base="http://example.org/a/" xml:lang="en" xml:space="preserve"><e1></e1></empty>`,
+			c14n11: `<empty xmlns:a="urn:a" xml:base="http://example.org/a/" xml:lang="en" xml:space="preserve"><e1></e1></empty>`,
+		},
+		{
+			path:   "//e1",
+			c14n10: `<e1 xmlns:a="urn:a" xml:base="http://example.org/a/" xml:lang="en" xml:space="preserve"></e1>`,
+			c14n11: `<e1 xmlns:a="urn:a" xml:base="http://example.org/a/" xml:lang="en" xml:space="preserve"></e1>`,
+		},
+	}
+
+	// Prepare the test case
+	testCaseDocument := etree.NewDocument()
+	err := testCaseDocument.ReadFromString(testCaseXml)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range testCases {
+		el := testCaseDocument.FindElement(tc.path)
+		if el == nil {
+			t.Fatalf("%s: element not found", tc.path)
+		}
+
+		// Canonicalize the test case subset with C14N 1.0
+		result, err := NewC14N10Canonicalizer(false).Canonicalize(el)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(result) != tc.c14n10 {
+			t.Fatalf("%s: %s.Canonicalize() = %s; want %s", tc.path, C14N10Algorithm, result, tc.c14n10)
+		}
+
+		// Canonicalize the test case subset with C14N 1.1
+		result, err = NewC14N11Canonicalizer(false).Canonicalize(el)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(result) != tc.c14n11 {
+			t.Fatalf("%s: %s.Canonicalize() = %s; want %s", tc.path, C14N11Algorithm, result, tc.c14n11)
+		}
+	}
+}
+
+func Test_joinXmlBase(t *testing.T) {
+	// Create test cases
+	testCases := []struct {
+		base     string
+		ref      string
+		expected string
+	}{
+		{
+			base:     "",
+			ref:      "sub/",
+			expected: "sub/",
+		},
+		{
+			base:     "http://example.org/a/b",
+			ref:      "c",
+			expected: "http://example.org/a/c",
+		},
+		{
+			base:     "http://example.org/a/",
+			ref:      "../c/",
+			expected: "http://example.org/c/",
+		},
+		{
+			base:     "http://example.org/a/",
+			ref:      "urn:other",
+			expected: "urn:other",
+		},
+		{
+			base:     "a/b/",
+			ref:      "../../../c",
+			expected: "../c",
+		},
+		{
+			base:     "a/b/",
+			ref:      "./c/.",
+			expected: "a/b/c/",
+		},
+		{
+			base:     "/a/",
+			ref:      "../../c",
+			expected: "/c",
+		},
+	}
+
+	for _, tc := range testCases {
+		result := joinXmlBase(tc.base, tc.ref)
+		if result != tc.expected {
+			t.Fatalf("joinXmlBase(%s, %s) = %s; want %s", tc.base, tc.ref, result, tc.expected)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package xmlsecurity
+
+import (
+	"github.com/beevik/etree"
+	"github.com/deb-ict/go-xml"
+)
+
+const (
+	ExcC14NAlgorithm             string = "http://www.w3.org/2001/10/xml-exc-c14n#"
+	ExcC14NWithCommentsAlgorithm string = "http://www.w3.org/2001/10/xml-exc-c14n#WithComments"
+	C14N10Algorithm              string = "http://www.w3.org/TR/2001/REC-xml-c14n-20010315"
+	C14N10WithCommentsAlgorithm  string = "http://www.w3.org/TR/2001/REC-xml-c14n-20010315#WithComments"
+	C14N11Algorithm              string = "http://www.w3.org/2006/12/xml-c14n11"
+	C14N11WithCommentsAlgorithm  string = "http://www.w3.org/2006/12/xml-c14n11#WithComments"
+)
+
+type CanonicalizationMethod interface {
+	xml.Node
+	GetAlgorithm() string
+	SetAlgorithm(algorithm string)
+	GetInclusiveNamespaces() []string
+	SetInclusiveNamespaces(prefixes []string)
+	GetCanonicalizer() (Canonicalizer, error)
+}
+
+type canonicalizationMethod struct {
+	Algorithm           string
+	InclusiveNamespaces []string
+}
+
+func NewCanonicalizationMethod(context xml.Context) (CanonicalizationMethod, error) {
+	return &canonicalizationMethod{}, nil
+}
+
+func NewCanonicalizationMethodNode(context xml.Context) (xml.Node, error) {
+	return NewCanonicalizationMethod(context)
+}
+
+func (node *canonicalizationMethod) GetAlgorithm() string {
+	return node.Algorithm
+}
+
+func (node *canonicalizationMethod) SetAlgorithm(algorithm string) {
+	node.Algorithm = algorithm
+}
+
+func (node *canonicalizationMethod) GetInclusiveNamespaces() []string {
+	return node.InclusiveNamespaces
+}
+
+func (node *canonicalizationMethod) SetInclusiveNamespaces(prefixes []string) {
+	node.InclusiveNamespaces = prefixes
+}
+
+func (node *canonicalizationMethod) GetCanonicalizer() (Canonicalizer, error) {
+	return NewCanonicalizer(node.GetAlgorithm(), node.GetInclusiveNamespaces())
+}
+
+func (node *canonicalizationMethod) LoadXml(context xml.Context, el *etree.Element) error {
+	err := xml.ValidateElement(el, "CanonicalizationMethod", DsigNamespace)
+	if err != nil {
+		return err
+	}
+
+	node.SetAlgorithm(el.SelectAttrValue("Algorithm", ""))
+	node.SetInclusiveNamespaces(loadInclusiveNamespaces(context, el))
+
+	return nil
+}
+
+func (node *canonicalizationMethod) GetXml(context xml.Context) (*etree.Element, error) {
+	el := etree.NewElement("CanonicalizationMethod")
+	el.Space = context.GetNamespacePrefix(DsigNamespace)
+
+	el.CreateAttr("Algorithm", node.GetAlgorithm())
+	addInclusiveNamespaces(context, el, node.GetInclusiveNamespaces())
+
+	return el, nil
+}
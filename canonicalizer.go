@@ -0,0 +1,297 @@
+package xmlsecurity
+
+import (
+	"bytes"
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/beevik/etree"
+	"github.com/deb-ict/go-xml"
+)
+
+var (
+	ErrUnsupportedCanonicalizationAlgorithm = errors.New("unsupported canonicalization algorithm")
+	ErrUndeclaredNamespacePrefix            = errors.New("undeclared namespace prefix")
+)
+
+type Canonicalizer interface {
+	GetAlgorithm() string
+	Canonicalize(el *etree.Element) ([]byte, error)
+	CanonicalizeDocument(doc *etree.Document) ([]byte, error)
+}
+
+type canonicalizationMode int
+
+const (
+	c14n10Mode canonicalizationMode = iota
+	c14n11Mode
+	excC14NMode
+)
+
+type canonicalizer struct {
+	algorithm           string
+	mode                canonicalizationMode
+	withComments        bool
+	inclusiveNamespaces map[string]bool
+}
+
+type canonicalAttr struct {
+	namespaceUri string
+	key          string
+	qname        string
+	value        string
+}
+
+func NewCanonicalizer(algorithm string, inclusiveNamespaces []string) (Canonicalizer, error) {
+	switch algorithm {
+	case ExcC14NAlgorithm:
+		return NewExcC14NCanonicalizer(false, inclusiveNamespaces...), nil
+	case ExcC14NWithCommentsAlgorithm:
+		return NewExcC14NCanonicalizer(true, inclusiveNamespaces...), nil
+	case C14N10Algorithm:
+		return NewC14N10Canonicalizer(false), nil
+	case C14N10WithCommentsAlgorithm:
+		return NewC14N10Canonicalizer(true), nil
+	case C14N11Algorithm:
+		return NewC14N11Canonicalizer(false), nil
+	case C14N11WithCommentsAlgorithm:
+		return NewC14N11Canonicalizer(true), nil
+	}
+	return nil, ErrUnsupportedCanonicalizationAlgorithm
+}
+
+func (c *canonicalizer) GetAlgorithm() string {
+	return c.algorithm
+}
+
+func (c *canonicalizer) Canonicalize(el *etree.Element) ([]byte, error) {
+	if el == nil {
+		return nil, xml.ErrElementIsNil
+	}
+
+	// Namespaces declared on ancestors are in scope for the subtree
+	inScope := make(map[string]string)
+	ancestors := make([]*etree.Element, 0)
+	for parent := el.Parent(); parent != nil; parent = parent.Parent() {
+		ancestors = append(ancestors, parent)
+	}
+	for i := len(ancestors) - 1; i >= 0; i-- {
+		inScope = declaredNamespaces(ancestors[i], inScope)
+	}
+
+	buf := &bytes.Buffer{}
+	err := c.writeElement(buf, el, inScope, map[string]string{}, c.inheritedXmlAttrs(el, ancestors))
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (c *canonicalizer) CanonicalizeDocument(doc *etree.Document) ([]byte, error) {
+	if doc == nil || doc.Root() == nil {
+		return nil, xml.ErrElementIsNil
+	}
+
+	// Nodes outside the document element are separated from it by line feeds
+	buf := &bytes.Buffer{}
+	afterRoot := false
+	for _, token := range doc.Child {
+		var node []byte
+		switch token := token.(type) {
+		case *etree.Element:
+			root, err := c.Canonicalize(token)
+			if err != nil {
+				return nil, err
+			}
+			buf.Write(root)
+			afterRoot = true
+			continue
+		case *etree.ProcInst:
+			if token.Target == "xml" {
+				continue
+			}
+			procInstBuf := &bytes.Buffer{}
+			writeCanonicalProcInst(procInstBuf, token)
+			node = procInstBuf.Bytes()
+		case *etree.Comment:
+			if !c.withComments {
+				continue
+			}
+			node = []byte("<!--" + token.Data + "-->")
+		default:
+			continue
+		}
+		if afterRoot {
+			buf.WriteString("\n")
+			buf.Write(node)
+		} else {
+			buf.Write(node)
+			buf.WriteString("\n")
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+func (c *canonicalizer) writeElement(buf *bytes.Buffer, el *etree.Element, parentInScope map[string]string, rendered map[string]string, inherited []etree.Attr) error {
+	inScope := declaredNamespaces(el, parentInScope)
+	if _, found := inScope[el.Space]; el.Space != "" && !found {
+		return ErrUndeclaredNamespacePrefix
+	}
+
+	var namespaces []string
+	if c.mode == excC14NMode {
+		namespaces = c.exclusiveNamespaces(el, inScope, rendered)
+	} else {
+		namespaces = c.inScopeNamespaces(inScope, rendered)
+	}
+	if len(namespaces) > 0 {
+		renderedCopy := make(map[string]string, len(rendered)+len(namespaces))
+		for prefix, uri := range rendered {
+			renderedCopy[prefix] = uri
+		}
+		for _, prefix := range namespaces {
+			renderedCopy[prefix] = inScope[prefix]
+		}
+		rendered = renderedCopy
+	}
+
+	attrs, err := canonicalAttrs(el, inScope, inherited)
+	if err != nil {
+		return err
+	}
+
+	buf.WriteString("<")
+	buf.WriteString(el.FullTag())
+	for _, prefix := range namespaces {
+		if prefix == "" {
+			buf.WriteString(` xmlns="`)
+		} else {
+			buf.WriteString(` xmlns:` + prefix + `="`)
+		}
+		buf.WriteString(escapeCanonicalAttr(inScope[prefix]))
+		buf.WriteString(`"`)
+	}
+	for _, attr := range attrs {
+		buf.WriteString(" " + attr.qname + `="`)
+		buf.WriteString(escapeCanonicalAttr(attr.value))
+		buf.WriteString(`"`)
+	}
+	buf.WriteString(">")
+
+	for _, token := range el.Child {
+		switch token := token.(type) {
+		case *etree.Element:
+			err := c.writeElement(buf, token, inScope, rendered, nil)
+			if err != nil {
+				return err
+			}
+		case *etree.CharData:
+			buf.WriteString(escapeCanonicalText(token.Data))
+		case *etree.Comment:
+			if c.withComments {
+				buf.WriteString("<!--" + token.Data + "-->")
+			}
+		case *etree.ProcInst:
+			writeCanonicalProcInst(buf, token)
+		}
+	}
+
+	buf.WriteString("</")
+	buf.WriteString(el.FullTag())
+	buf.WriteString(">")
+	return nil
+}
+
+func declaredNamespaces(el *etree.Element, parentInScope map[string]string) map[string]string {
+	inScope := parentInScope
+	copied := false
+	for _, attr := range el.Attr {
+		var prefix string
+		switch {
+		case attr.Space == "" && attr.Key == "xmlns":
+			prefix = ""
+		case attr.Space == "xmlns":
+			prefix = attr.Key
+		default:
+			continue
+		}
+		if !copied {
+			inScope = make(map[string]string, len(parentInScope)+1)
+			for key, value := range parentInScope {
+				inScope[key] = value
+			}
+			copied = true
+		}
+		inScope[prefix] = attr.Value
+	}
+	return inScope
+}
+
+func canonicalAttrs(el *etree.Element, inScope map[string]string, inherited []etree.Attr) ([]canonicalAttr, error) {
+	attrs := make([]canonicalAttr, 0, len(el.Attr)+len(inherited))
+	for _, attr := range mergeXmlAttrs(el.Attr, inherited) {
+		if (attr.Space == "" && attr.Key == "xmlns") || attr.Space == "xmlns" {
+			continue
+		}
+		namespaceUri := ""
+		switch attr.Space {
+		case "":
+		case "xml":
+			namespaceUri = XmlNamespace
+		default:
+			uri, found := inScope[attr.Space]
+			if !found || uri == "" {
+				return nil, ErrUndeclaredNamespacePrefix
+			}
+			namespaceUri = uri
+		}
+		attrs = append(attrs, canonicalAttr{
+			namespaceUri: namespaceUri,
+			key:          attr.Key,
+			qname:        attr.FullKey(),
+			value:        attr.Value,
+		})
+	}
+
+	// Unqualified attributes sort first, then by namespace URI and local name
+	sort.SliceStable(attrs, func(i, j int) bool {
+		if attrs[i].namespaceUri != attrs[j].namespaceUri {
+			return attrs[i].namespaceUri < attrs[j].namespaceUri
+		}
+		return attrs[i].key < attrs[j].key
+	})
+	return attrs, nil
+}
+
+func writeCanonicalProcInst(buf *bytes.Buffer, procInst *etree.ProcInst) {
+	buf.WriteString("<?" + procInst.Target)
+	if procInst.Inst != "" {
+		buf.WriteString(" " + procInst.Inst)
+	}
+	buf.WriteString("?>")
+}
+
+var canonicalTextReplacer = strings.NewReplacer(
+	"&", "&amp;",
+	"<", "&lt;",
+	">", "&gt;",
+	"\r", "&#xD;",
+)
+
+var canonicalAttrReplacer = strings.NewReplacer(
+	"&", "&amp;",
+	"<", "&lt;",
+	`"`, "&quot;",
+	"\t", "&#x9;",
+	"\n", "&#xA;",
+	"\r", "&#xD;",
+)
+
+func escapeCanonicalText(value string) string {
+	return canonicalTextReplacer.Replace(value)
+}
+
+func escapeCanonicalAttr(value string) string {
+	return canonicalAttrReplacer.Replace(value)
+}
@@ -0,0 +1,162 @@
+package xmlsecurity
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	ErrNoCertificatesFound = errors.New("no certificates found")
+)
+
+var certificateFileExtensions = []string{".pem", ".crt", ".cer", ".cert", ".der"}
+
+type CertificateLoadError struct {
+	Path string
+	Err  error
+}
+
+func (e *CertificateLoadError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Path, e.Err)
+}
+
+func (e *CertificateLoadError) Unwrap() error {
+	return e.Err
+}
+
+func ParseCertificates(data []byte) ([]*x509.Certificate, error) {
+	if !bytes.Contains(data, []byte("-----BEGIN")) {
+		certificates, err := x509.ParseCertificates(data)
+		if err != nil {
+			return nil, err
+		}
+		if len(certificates) == 0 {
+			return nil, ErrNoCertificatesFound
+		}
+		return certificates, nil
+	}
+
+	certificates := make([]*x509.Certificate, 0)
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+
+		certificate, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		certificates = append(certificates, certificate)
+	}
+	if len(certificates) == 0 {
+		return nil, ErrNoCertificatesFound
+	}
+
+	return certificates, nil
+}
+
+func LoadCertificateStoreFromFile(name string) (MemoryCertificateStore, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	certificates, err := ParseCertificates(data)
+	if err != nil {
+		return nil, &CertificateLoadError{Path: name, Err: err}
+	}
+
+	return NewMemoryCertificateStore(certificates...), nil
+}
+
+func LoadCertificateStoreFromDirectory(dir string) (MemoryCertificateStore, []*CertificateLoadError, error) {
+	store, failures, err := LoadCertificateStoreFromFS(os.DirFS(dir), ".")
+	localizeLoadErrors(dir, failures)
+	return store, failures, err
+}
+
+func LoadCertificateStoreFromFS(fsys fs.FS, root string) (MemoryCertificateStore, []*CertificateLoadError, error) {
+	store := NewMemoryCertificateStore()
+	failures, err := loadFilesFromFS(fsys, root, isCertificateFile, func(data []byte) error {
+		certificates, err := ParseCertificates(data)
+		if err != nil {
+			return err
+		}
+		for _, certificate := range certificates {
+			store.AddCertificate(certificate)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return store, failures, nil
+}
+
+func loadFilesFromFS(fsys fs.FS, root string, match func(name string) bool, load func(data []byte) error) ([]*CertificateLoadError, error) {
+	failures := make([]*CertificateLoadError, 0)
+	err := fs.WalkDir(fsys, root, func(name string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if name == root {
+				return err
+			}
+			failures = append(failures, &CertificateLoadError{Path: name, Err: err})
+			return nil
+		}
+		if entry.IsDir() || !match(name) {
+			return nil
+		}
+
+		data, err := fs.ReadFile(fsys, name)
+		if err == nil {
+			err = load(data)
+		}
+		if err != nil {
+			failures = append(failures, &CertificateLoadError{Path: name, Err: err})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return failures, nil
+}
+
+func localizeLoadErrors(dir string, failures []*CertificateLoadError) {
+	for _, failure := range failures {
+		failure.Path = filepath.Join(dir, filepath.FromSlash(failure.Path))
+	}
+}
+
+func isCertificateFile(name string) bool {
+	return hasFileExtension(name, certificateFileExtensions)
+}
+
+func hasFileExtension(name string, extensions []string) bool {
+	base := path.Base(name)
+	if strings.HasPrefix(base, ".") {
+		return false
+	}
+
+	extension := strings.ToLower(path.Ext(base))
+	for _, candidate := range extensions {
+		if extension == candidate {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,149 @@
+package xmlsecurity
+
+import (
+	"encoding/pem"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+func Test_ParseCertificates(t *testing.T) {
+	root := newTestCertificate(t, "Test Root", nil, nil)
+	leaf := newTestCertificate(t, "Test Leaf", root, nil)
+
+	// Create test case
+	pemData := append(
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: leaf.Certificate.Raw}),
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: root.Certificate.Raw})...,
+	)
+	testCase := []struct {
+		data     []byte
+		expected int
+		err      bool
+	}{
+		{data: pemData, expected: 2},
+		{data: leaf.Certificate.Raw, expected: 1},
+		{data: append(append([]byte{}, leaf.Certificate.Raw...), root.Certificate.Raw...), expected: 2},
+		{data: pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: []byte{0x01}}), err: true},
+		{data: []byte("not a certificate"), err: true},
+	}
+
+	for i, tc := range testCase {
+		certificates, err := ParseCertificates(tc.data)
+		if tc.err {
+			if err == nil {
+				t.Fatalf("ParseCertificates() case %d: expected error", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("ParseCertificates() case %d: %v", i, err)
+		}
+		if len(certificates) != tc.expected {
+			t.Fatalf("ParseCertificates() case %d: len = %d; want %d", i, len(certificates), tc.expected)
+		}
+	}
+}
+
+func Test_LoadCertificateStoreFromFS(t *testing.T) {
+	root := newTestCertificate(t, "Test Root", nil, nil)
+	leaf := newTestCertificate(t, "Test Leaf", root, nil)
+
+	// Create test case file system
+	fsys := fstest.MapFS{
+		"certs/root.pem":         {Data: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: root.Certificate.Raw})},
+		"certs/partner/leaf.cer": {Data: leaf.Certificate.Raw},
+		"certs/broken.crt":       {Data: []byte("broken")},
+		"certs/README.md":        {Data: []byte("partner certificates")},
+		"certs/.hidden.pem":      {Data: []byte("hidden")},
+	}
+
+	// Load the test case store
+	store, failures, err := LoadCertificateStoreFromFS(fsys, "certs")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Validate the test case store
+	if len(store.GetCertificates()) != 2 {
+		t.Fatalf("len(Certificates) = %d; want 2", len(store.GetCertificates()))
+	}
+	if len(failures) != 1 {
+		t.Fatalf("len(failures) = %d; want 1", len(failures))
+	}
+	if failures[0].Path != "certs/broken.crt" {
+		t.Fatalf("failures[0].Path = %s; want certs/broken.crt", failures[0].Path)
+	}
+	_, err = store.FindBySubjectKeyId(root.Certificate.SubjectKeyId)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_LoadCertificateStoreFromFS_MissingRoot(t *testing.T) {
+	_, _, err := LoadCertificateStoreFromFS(fstest.MapFS{}, "missing")
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("LoadCertificateStoreFromFS() = %v; want %v", err, os.ErrNotExist)
+	}
+}
+
+func Test_LoadCertificateStoreFromDirectory(t *testing.T) {
+	certificate := newTestCertificate(t, "Test", nil, nil)
+
+	// Prepare the test case directory
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, "test.der"), certificate.Certificate.Raw, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(filepath.Join(dir, "broken.pem"), []byte("broken"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Load the test case store
+	store, failures, err := LoadCertificateStoreFromDirectory(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(store.GetCertificates()) != 1 {
+		t.Fatalf("len(Certificates) = %d; want 1", len(store.GetCertificates()))
+	}
+	if len(failures) != 1 || failures[0].Path != filepath.Join(dir, "broken.pem") {
+		t.Fatalf("failures = %v; want %s", failures, filepath.Join(dir, "broken.pem"))
+	}
+}
+
+func Test_LoadCertificateStoreFromFile(t *testing.T) {
+	certificate := newTestCertificate(t, "Test", nil, nil)
+
+	// Prepare the test case file
+	dir := t.TempDir()
+	name := filepath.Join(dir, "test.pem")
+	err := os.WriteFile(name, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certificate.Certificate.Raw}), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Load the test case store
+	store, err := LoadCertificateStoreFromFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(store.GetCertificates()) != 1 {
+		t.Fatalf("len(Certificates) = %d; want 1", len(store.GetCertificates()))
+	}
+
+	// Load an invalid file
+	err = os.WriteFile(name, []byte("broken"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = LoadCertificateStoreFromFile(name)
+	var loadError *CertificateLoadError
+	if !errors.As(err, &loadError) || loadError.Path != name {
+		t.Fatalf("LoadCertificateStoreFromFile() = %v; want CertificateLoadError", err)
+	}
+}
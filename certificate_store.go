@@ -0,0 +1,62 @@
+package xmlsecurity
+
+import (
+	"crypto/x509"
+	"errors"
+	"math/big"
+	"strings"
+)
+
+var (
+	ErrCertificateNotFound  = errors.New("certificate not found")
+	ErrCertificateAmbiguous = errors.New("certificate is ambiguous")
+)
+
+type CertificateStore interface {
+	FindByThumbprintSHA1(thumbprint []byte) (*x509.Certificate, error)
+	FindByThumbprintSHA256(thumbprint []byte) (*x509.Certificate, error)
+	FindBySubjectKeyId(subjectKeyId []byte) (*x509.Certificate, error)
+	FindByIssuerSerial(issuer string, serialNumber *big.Int) (*x509.Certificate, error)
+	FindBySubject(subject string) ([]*x509.Certificate, error)
+}
+
+func normalizeDistinguishedName(name string) string {
+	rdns := make([]string, 0)
+	current := strings.Builder{}
+	escaped := false
+	for _, r := range name {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			current.WriteRune(r)
+			escaped = true
+		case r == ',' || r == ';':
+			rdns = append(rdns, normalizeRelativeDistinguishedName(current.String()))
+			current.Reset()
+		default:
+			current.WriteRune(r)
+		}
+	}
+	rdns = append(rdns, normalizeRelativeDistinguishedName(current.String()))
+
+	return strings.Join(rdns, ",")
+}
+
+func normalizeRelativeDistinguishedName(rdn string) string {
+	attributeType, value, found := strings.Cut(rdn, "=")
+	if !found {
+		return strings.ToLower(strings.TrimSpace(rdn))
+	}
+
+	attributeType = strings.ToUpper(strings.TrimSpace(attributeType))
+	switch attributeType {
+	case "E", "EMAIL", "EMAILADDRESS", "OID.1.2.840.113549.1.9.1":
+		attributeType = "1.2.840.113549.1.9.1"
+	case "S", "ST":
+		attributeType = "ST"
+	}
+
+	return attributeType + "=" + strings.ToLower(strings.TrimSpace(value))
+}
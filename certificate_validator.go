@@ -0,0 +1,242 @@
+package xmlsecurity
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+
+	"github.com/deb-ict/go-xml"
+)
+
+type CertificateValidationReason int
+
+const (
+	CertificateUntrusted CertificateValidationReason = iota
+	CertificateExpired
+	CertificateNotYetValid
+	CertificateKeyUsageNotAllowed
+	CertificateExtKeyUsageNotAllowed
+	CertificateInvalidChain
+	CertificateRevoked
+	CertificateRevocationUnknown
+)
+
+type CertificateValidationError struct {
+	Reason      CertificateValidationReason
+	Certificate *x509.Certificate
+	Err         error
+}
+
+type CertificateValidatorOptions struct {
+	Roots              []*x509.Certificate
+	Intermediates      []*x509.Certificate
+	Clock              Clock
+	KeyUsage           x509.KeyUsage
+	ExtKeyUsages       []x509.ExtKeyUsage
+	RevocationCheckers []RevocationChecker
+}
+
+type CertificateValidator interface {
+	Validate(certificate *x509.Certificate, intermediates ...*x509.Certificate) ([]*x509.Certificate, error)
+	ValidateProvider(context xml.Context, provider X509CertificateProvider) ([]*x509.Certificate, error)
+}
+
+type certificateValidator struct {
+	roots              *x509.CertPool
+	intermediates      []*x509.Certificate
+	clock              Clock
+	keyUsage           x509.KeyUsage
+	extKeyUsages       []x509.ExtKeyUsage
+	revocationCheckers []RevocationChecker
+}
+
+func NewCertificateValidator(options CertificateValidatorOptions) CertificateValidator {
+	roots := x509.NewCertPool()
+	for _, root := range options.Roots {
+		roots.AddCert(root)
+	}
+	clock := options.Clock
+	if clock == nil {
+		clock = SystemClock
+	}
+	extKeyUsages := options.ExtKeyUsages
+	if len(extKeyUsages) == 0 {
+		// x509.Verify defaults to server authentication when no usage is given
+		extKeyUsages = []x509.ExtKeyUsage{x509.ExtKeyUsageAny}
+	}
+
+	return &certificateValidator{
+		roots:              roots,
+		intermediates:      options.Intermediates,
+		clock:              clock,
+		keyUsage:           options.KeyUsage,
+		extKeyUsages:       extKeyUsages,
+		revocationCheckers: options.RevocationCheckers,
+	}
+}
+
+func (reason CertificateValidationReason) String() string {
+	switch reason {
+	case CertificateUntrusted:
+		return "certificate is not trusted"
+	case CertificateExpired:
+		return "certificate has expired"
+	case CertificateNotYetValid:
+		return "certificate is not yet valid"
+	case CertificateKeyUsageNotAllowed:
+		return "certificate key usage not allowed"
+	case CertificateExtKeyUsageNotAllowed:
+		return "certificate extended key usage not allowed"
+	case CertificateInvalidChain:
+		return "certificate chain is invalid"
+	case CertificateRevoked:
+		return "certificate has been revoked"
+	case CertificateRevocationUnknown:
+		return "certificate revocation status unknown"
+	default:
+		return "certificate validation failed"
+	}
+}
+
+func (e *CertificateValidationError) Error() string {
+	subject := ""
+	if e.Certificate != nil {
+		subject = e.Certificate.Subject.String()
+	}
+	if e.Err != nil {
+		return fmt.Sprintf("%s (%s): %v", e.Reason, subject, e.Err)
+	}
+	return fmt.Sprintf("%s (%s)", e.Reason, subject)
+}
+
+func (e *CertificateValidationError) Unwrap() error {
+	return e.Err
+}
+
+func (validator *certificateValidator) Validate(certificate *x509.Certificate, intermediates ...*x509.Certificate) ([]*x509.Certificate, error) {
+	if certificate == nil {
+		return nil, ErrX509CertificateNotAvailable
+	}
+
+	// x509.Verify reports both ends of the validity window as expired
+	now := validator.clock.Now()
+	if now.Before(certificate.NotBefore) {
+		return nil, &CertificateValidationError{Reason: CertificateNotYetValid, Certificate: certificate}
+	}
+	if now.After(certificate.NotAfter) {
+		return nil, &CertificateValidationError{Reason: CertificateExpired, Certificate: certificate}
+	}
+	if validator.keyUsage != 0 && certificate.KeyUsage&validator.keyUsage != validator.keyUsage {
+		return nil, &CertificateValidationError{Reason: CertificateKeyUsageNotAllowed, Certificate: certificate}
+	}
+
+	intermediatePool := x509.NewCertPool()
+	for _, intermediate := range validator.intermediates {
+		intermediatePool.AddCert(intermediate)
+	}
+	for _, intermediate := range intermediates {
+		if intermediate != nil && !intermediate.Equal(certificate) {
+			intermediatePool.AddCert(intermediate)
+		}
+	}
+
+	chains, err := certificate.Verify(x509.VerifyOptions{
+		Roots:         validator.roots,
+		Intermediates: intermediatePool,
+		CurrentTime:   now,
+		KeyUsages:     validator.extKeyUsages,
+	})
+	if err != nil {
+		return nil, newCertificateValidationError(certificate, err)
+	}
+
+	chain := chains[0]
+	err = validator.checkRevocation(chain)
+	if err != nil {
+		return nil, err
+	}
+
+	return chain, nil
+}
+
+func (validator *certificateValidator) checkRevocation(chain []*x509.Certificate) error {
+	if len(validator.revocationCheckers) == 0 {
+		return nil
+	}
+
+	// The trust anchor itself is not subject to revocation checking
+	for i := 0; i < len(chain)-1; i++ {
+		err := validator.checkCertificateRevocation(chain[i], chain[i+1])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (validator *certificateValidator) checkCertificateRevocation(certificate *x509.Certificate, issuer *x509.Certificate) error {
+	// A revoked result from any checker fails, otherwise a single good result passes
+	good := false
+	var unknownErr error
+	for _, checker := range validator.revocationCheckers {
+		err := checker.CheckRevocation(certificate, issuer)
+		switch {
+		case err == nil:
+			good = true
+		case errors.Is(err, ErrCertificateRevoked):
+			return &CertificateValidationError{Reason: CertificateRevoked, Certificate: certificate, Err: err}
+		case unknownErr == nil:
+			unknownErr = err
+		}
+	}
+	if good {
+		return nil
+	}
+	return &CertificateValidationError{Reason: CertificateRevocationUnknown, Certificate: certificate, Err: unknownErr}
+}
+
+func (validator *certificateValidator) ValidateProvider(context xml.Context, provider X509CertificateProvider) ([]*x509.Certificate, error) {
+	if chainProvider, ok := provider.(X509ChainProvider); ok {
+		chain, err := chainProvider.GetX509CertificateChain(context)
+		if err != nil {
+			return nil, err
+		}
+		if len(chain) == 0 {
+			return nil, ErrX509CertificateNotAvailable
+		}
+		return validator.Validate(chain[0], chain[1:]...)
+	}
+
+	certificate, err := provider.GetX509Certificate(context)
+	if err != nil {
+		return nil, err
+	}
+	return validator.Validate(certificate)
+}
+
+func newCertificateValidationError(certificate *x509.Certificate, err error) *CertificateValidationError {
+	result := &CertificateValidationError{
+		Reason:      CertificateInvalidChain,
+		Certificate: certificate,
+		Err:         err,
+	}
+
+	var unknownAuthorityError x509.UnknownAuthorityError
+	var invalidError x509.CertificateInvalidError
+	switch {
+	case errors.As(err, &unknownAuthorityError):
+		result.Reason = CertificateUntrusted
+	case errors.As(err, &invalidError):
+		if invalidError.Cert != nil {
+			result.Certificate = invalidError.Cert
+		}
+		switch invalidError.Reason {
+		case x509.Expired:
+			result.Reason = CertificateExpired
+		case x509.IncompatibleUsage:
+			result.Reason = CertificateExtKeyUsageNotAllowed
+		}
+	}
+
+	return result
+}
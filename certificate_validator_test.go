@@ -0,0 +1,223 @@
+package xmlsecurity
+
+import (
+	"crypto/x509"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/deb-ict/go-xml"
+)
+
+func newTestCertificateHierarchy(t *testing.T, modifyLeaf func(template *x509.Certificate)) (*testCertificate, *testCertificate, *testCertificate) {
+	t.Helper()
+
+	root := newTestCertificate(t, "Test Root", nil, nil)
+	intermediate := newTestCertificate(t, "Test Intermediate", root, func(template *x509.Certificate) {
+		template.IsCA = true
+		template.KeyUsage |= x509.KeyUsageCertSign | x509.KeyUsageCRLSign
+	})
+	leaf := newTestCertificate(t, "Test Leaf", intermediate, modifyLeaf)
+	return root, intermediate, leaf
+}
+
+func Test_CertificateValidator_Validate(t *testing.T) {
+	root, intermediate, leaf := newTestCertificateHierarchy(t, nil)
+
+	// Create the test case validator
+	validator := NewCertificateValidator(CertificateValidatorOptions{
+		Roots:         []*x509.Certificate{root.Certificate},
+		Intermediates: []*x509.Certificate{intermediate.Certificate},
+		KeyUsage:      x509.KeyUsageDigitalSignature,
+	})
+
+	// Validate the test case certificate
+	chain, err := validator.Validate(leaf.Certificate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(chain) != 3 || !chain[0].Equal(leaf.Certificate) || !chain[2].Equal(root.Certificate) {
+		t.Fatal("CertificateValidator.Validate() did not return the validated chain")
+	}
+}
+
+func Test_CertificateValidator_ValidateProvider_PKIPath(t *testing.T) {
+	root, intermediate, leaf := newTestCertificateHierarchy(t, nil)
+
+	// Create the test case token carrying the intermediate
+	token, err := NewX509ChainBinarySecurityToken(nil, []*x509.Certificate{leaf.Certificate, intermediate.Certificate})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Validate the test case token
+	validator := NewCertificateValidator(CertificateValidatorOptions{
+		Roots: []*x509.Certificate{root.Certificate},
+	})
+	chain, err := validator.ValidateProvider(nil, token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(chain) != 3 {
+		t.Fatalf("len(chain) = %d; want 3", len(chain))
+	}
+
+	// Without the intermediate the leaf is not trusted
+	token, err = NewX509BinarySecurityToken(nil, leaf.Certificate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = validator.ValidateProvider(nil, token)
+	var validationError *CertificateValidationError
+	if !errors.As(err, &validationError) || validationError.Reason != CertificateUntrusted {
+		t.Fatalf("CertificateValidator.ValidateProvider() = %v; want %s", err, CertificateUntrusted)
+	}
+}
+
+func Test_CertificateValidator_Validate_Errors(t *testing.T) {
+	root, intermediate, leaf := newTestCertificateHierarchy(t, func(template *x509.Certificate) {
+		template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}
+	})
+	otherRoot := newTestCertificate(t, "Other Root", nil, nil)
+
+	// Create test case
+	testCase := []struct {
+		name     string
+		options  CertificateValidatorOptions
+		expected CertificateValidationReason
+	}{
+		{
+			name:     "untrusted",
+			options:  CertificateValidatorOptions{Roots: []*x509.Certificate{otherRoot.Certificate}},
+			expected: CertificateUntrusted,
+		},
+		{
+			name: "expired",
+			options: CertificateValidatorOptions{
+				Roots: []*x509.Certificate{root.Certificate},
+				Clock: ClockFunc(func() time.Time { return leaf.Certificate.NotAfter.Add(time.Minute) }),
+			},
+			expected: CertificateExpired,
+		},
+		{
+			name: "not yet valid",
+			options: CertificateValidatorOptions{
+				Roots: []*x509.Certificate{root.Certificate},
+				Clock: ClockFunc(func() time.Time { return leaf.Certificate.NotBefore.Add(-time.Minute) }),
+			},
+			expected: CertificateNotYetValid,
+		},
+		{
+			name: "key usage",
+			options: CertificateValidatorOptions{
+				Roots:    []*x509.Certificate{root.Certificate},
+				KeyUsage: x509.KeyUsageKeyEncipherment,
+			},
+			expected: CertificateKeyUsageNotAllowed,
+		},
+		{
+			name: "extended key usage",
+			options: CertificateValidatorOptions{
+				Roots:        []*x509.Certificate{root.Certificate},
+				ExtKeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+			},
+			expected: CertificateExtKeyUsageNotAllowed,
+		},
+	}
+
+	for _, tc := range testCase {
+		validator := NewCertificateValidator(tc.options)
+
+		_, err := validator.Validate(leaf.Certificate, intermediate.Certificate)
+		var validationError *CertificateValidationError
+		if !errors.As(err, &validationError) {
+			t.Fatalf("%s: CertificateValidator.Validate() = %v; want CertificateValidationError", tc.name, err)
+		}
+		if validationError.Reason != tc.expected {
+			t.Fatalf("%s: CertificateValidationError.Reason = %s; want %s", tc.name, validationError.Reason, tc.expected)
+		}
+	}
+}
+
+type testRevocationChecker struct {
+	err error
+}
+
+func (checker testRevocationChecker) CheckRevocation(certificate *x509.Certificate, issuer *x509.Certificate) error {
+	return checker.err
+}
+
+func Test_CertificateValidator_Validate_CombinedRevocation(t *testing.T) {
+	root, intermediate, leaf := newTestCertificateHierarchy(t, nil)
+	good := testRevocationChecker{}
+	revoked := testRevocationChecker{err: ErrCertificateRevoked}
+	unknown := testRevocationChecker{err: ErrRevocationStatusUnknown}
+
+	// Create test case
+	testCase := []struct {
+		name     string
+		checkers []RevocationChecker
+		err      error
+	}{
+		{
+			name:     "unknown then good",
+			checkers: []RevocationChecker{unknown, good},
+			err:      nil,
+		},
+		{
+			name:     "good then unknown",
+			checkers: []RevocationChecker{good, unknown},
+			err:      nil,
+		},
+		{
+			name:     "good then revoked",
+			checkers: []RevocationChecker{good, revoked},
+			err:      ErrCertificateRevoked,
+		},
+		{
+			name:     "unknown then revoked",
+			checkers: []RevocationChecker{unknown, revoked},
+			err:      ErrCertificateRevoked,
+		},
+		{
+			name:     "all unknown",
+			checkers: []RevocationChecker{unknown, unknown},
+			err:      ErrRevocationStatusUnknown,
+		},
+	}
+
+	for _, tc := range testCase {
+		validator := NewCertificateValidator(CertificateValidatorOptions{
+			Roots:              []*x509.Certificate{root.Certificate},
+			Intermediates:      []*x509.Certificate{intermediate.Certificate},
+			RevocationCheckers: tc.checkers,
+		})
+
+		_, err := validator.Validate(leaf.Certificate)
+		if !errors.Is(err, tc.err) || (tc.err == nil && err != nil) {
+			t.Fatalf("%s: CertificateValidator.Validate() = %v; want %v", tc.name, err, tc.err)
+		}
+	}
+}
+
+type testEmptyChainProvider struct{}
+
+func (provider testEmptyChainProvider) GetX509Certificate(context xml.Context) (*x509.Certificate, error) {
+	return nil, ErrX509CertificateNotAvailable
+}
+
+func (provider testEmptyChainProvider) GetX509CertificateChain(context xml.Context) ([]*x509.Certificate, error) {
+	return []*x509.Certificate{}, nil
+}
+
+func Test_CertificateValidator_ValidateProvider_EmptyChain(t *testing.T) {
+	root := newTestCertificate(t, "Test Root", nil, nil)
+	validator := NewCertificateValidator(CertificateValidatorOptions{
+		Roots: []*x509.Certificate{root.Certificate},
+	})
+
+	_, err := validator.ValidateProvider(nil, testEmptyChainProvider{})
+	if err != ErrX509CertificateNotAvailable {
+		t.Fatalf("CertificateValidator.ValidateProvider() = %v; want %v", err, ErrX509CertificateNotAvailable)
+	}
+}
@@ -0,0 +1,21 @@
+package xmlsecurity
+
+import "time"
+
+type Clock interface {
+	Now() time.Time
+}
+
+type ClockFunc func() time.Time
+
+type systemClock struct{}
+
+var SystemClock Clock = systemClock{}
+
+func (f ClockFunc) Now() time.Time {
+	return f()
+}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
@@ -0,0 +1,133 @@
+package xmlsecurity
+
+import (
+	"github.com/deb-ict/go-xml"
+)
+
+type Context interface {
+	xml.Context
+	GetCertificateStore() CertificateStore
+	SetCertificateStore(store CertificateStore)
+	GetKeyUsagePolicy() KeyUsagePolicy
+	SetKeyUsagePolicy(policy KeyUsagePolicy)
+	GetIdResolver() IdResolver
+	SetIdResolver(resolver IdResolver)
+	GetIdIndex() (IdIndex, error)
+	ResetIdIndex()
+	RetainIdIndex()
+	ReleaseIdIndex()
+	GetIdGenerator() IdGenerator
+	SetIdGenerator(generator IdGenerator)
+	GetMaxReferenceDepth() int
+	SetMaxReferenceDepth(depth int)
+}
+
+type securityContext struct {
+	xml.Context
+	certificateStore  CertificateStore
+	keyUsagePolicy    KeyUsagePolicy
+	idResolver        IdResolver
+	idIndex           IdIndex
+	idIndexRetained   int
+	idGenerator       IdGenerator
+	maxReferenceDepth int
+}
+
+func NewContext(context xml.Context) Context {
+	if securityContext, ok := context.(Context); ok {
+		return securityContext
+	}
+	return &securityContext{
+		Context: context,
+	}
+}
+
+func GetCertificateStore(context xml.Context) CertificateStore {
+	securityContext, ok := context.(Context)
+	if !ok {
+		return nil
+	}
+	return securityContext.GetCertificateStore()
+}
+
+func GetKeyUsagePolicy(context xml.Context) KeyUsagePolicy {
+	securityContext, ok := context.(Context)
+	if !ok {
+		return nil
+	}
+	return securityContext.GetKeyUsagePolicy()
+}
+
+func (ctx *securityContext) GetCertificateStore() CertificateStore {
+	return ctx.certificateStore
+}
+
+func (ctx *securityContext) SetCertificateStore(store CertificateStore) {
+	ctx.certificateStore = store
+}
+
+func (ctx *securityContext) GetKeyUsagePolicy() KeyUsagePolicy {
+	return ctx.keyUsagePolicy
+}
+
+func (ctx *securityContext) SetKeyUsagePolicy(policy KeyUsagePolicy) {
+	ctx.keyUsagePolicy = policy
+}
+
+func (ctx *securityContext) GetIdResolver() IdResolver {
+	return ctx.idResolver
+}
+
+func (ctx *securityContext) SetIdResolver(resolver IdResolver) {
+	ctx.idResolver = resolver
+	ctx.idIndex = nil
+}
+
+func (ctx *securityContext) GetIdIndex() (IdIndex, error) {
+	if ctx.idIndex != nil {
+		return ctx.idIndex, nil
+	}
+
+	// The index is only cached while an operation retains it, the document may change in between
+	index, err := NewIdIndex(ctx, GetIdResolver(ctx))
+	if err != nil {
+		return nil, err
+	}
+	if ctx.idIndexRetained > 0 {
+		ctx.idIndex = index
+	}
+	return index, nil
+}
+
+func (ctx *securityContext) ResetIdIndex() {
+	ctx.idIndex = nil
+}
+
+func (ctx *securityContext) RetainIdIndex() {
+	ctx.idIndexRetained++
+}
+
+func (ctx *securityContext) ReleaseIdIndex() {
+	if ctx.idIndexRetained > 0 {
+		ctx.idIndexRetained--
+	}
+	if ctx.idIndexRetained == 0 {
+		ctx.idIndex = nil
+	}
+}
+
+func (ctx *securityContext) GetIdGenerator() IdGenerator {
+	return ctx.idGenerator
+}
+
+func (ctx *securityContext) SetIdGenerator(generator IdGenerator) {
+	ctx.idGenerator = generator
+}
+
+func (ctx *securityContext) GetMaxReferenceDepth() int {
+	return ctx.maxReferenceDepth
+}
+
+func (ctx *securityContext) SetMaxReferenceDepth(depth int) {
+	ctx.maxReferenceDepth = depth
+}
@@ -0,0 +1,32 @@
+package xmlsecurity
+
+import (
+	"testing"
+
+	"github.com/beevik/etree"
+	"github.com/deb-ict/go-xml"
+)
+
+func Test_NewContext(t *testing.T) {
+	testCaseDocument := etree.NewDocument()
+	xmlContext := xml.NewContext(testCaseDocument)
+
+	// Create test case Context
+	testCaseContext := NewContext(xmlContext)
+	if testCaseContext.GetDocument() != testCaseDocument {
+		t.Fatal("Context.GetDocument() did not return the wrapped document")
+	}
+	if NewContext(testCaseContext) != testCaseContext {
+		t.Fatal("NewContext() wrapped an existing Context")
+	}
+
+	// Attach a certificate store
+	if GetCertificateStore(xmlContext) != nil {
+		t.Fatal("GetCertificateStore() = not nil; want nil")
+	}
+	store := NewMemoryCertificateStore()
+	testCaseContext.SetCertificateStore(store)
+	if GetCertificateStore(testCaseContext) != store {
+		t.Fatal("GetCertificateStore() did not return the attached store")
+	}
+}
@@ -0,0 +1,172 @@
+package xmlsecurity
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"io/fs"
+	"os"
+	"sync"
+)
+
+var (
+	ErrNoCrlsFound = errors.New("no crls found")
+)
+
+var crlFileExtensions = []string{".crl", ".pem"}
+
+type CrlRevocationChecker interface {
+	RevocationChecker
+	AddCrl(crl *x509.RevocationList)
+	GetCrls() []*x509.RevocationList
+}
+
+type crlRevocationChecker struct {
+	mutex sync.RWMutex
+	clock Clock
+	crls  []*x509.RevocationList
+}
+
+func NewCrlRevocationChecker(clock Clock, crls ...*x509.RevocationList) CrlRevocationChecker {
+	if clock == nil {
+		clock = SystemClock
+	}
+
+	checker := &crlRevocationChecker{
+		clock: clock,
+		crls:  make([]*x509.RevocationList, 0),
+	}
+	for _, crl := range crls {
+		checker.AddCrl(crl)
+	}
+	return checker
+}
+
+func ParseCrls(data []byte) ([]*x509.RevocationList, error) {
+	if !bytes.Contains(data, []byte("-----BEGIN")) {
+		crl, err := x509.ParseRevocationList(data)
+		if err != nil {
+			return nil, err
+		}
+		return []*x509.RevocationList{crl}, nil
+	}
+
+	crls := make([]*x509.RevocationList, 0)
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "X509 CRL" {
+			continue
+		}
+
+		crl, err := x509.ParseRevocationList(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		crls = append(crls, crl)
+	}
+	if len(crls) == 0 {
+		return nil, ErrNoCrlsFound
+	}
+
+	return crls, nil
+}
+
+func LoadCrlRevocationCheckerFromDirectory(dir string, clock Clock) (CrlRevocationChecker, []*CertificateLoadError, error) {
+	checker, failures, err := LoadCrlRevocationCheckerFromFS(os.DirFS(dir), ".", clock)
+	localizeLoadErrors(dir, failures)
+	return checker, failures, err
+}
+
+func LoadCrlRevocationCheckerFromFS(fsys fs.FS, root string, clock Clock) (CrlRevocationChecker, []*CertificateLoadError, error) {
+	checker := NewCrlRevocationChecker(clock)
+	failures, err := loadFilesFromFS(fsys, root, isCrlFile, func(data []byte) error {
+		// PEM files holding only certificates may share the directory
+		crls, err := ParseCrls(data)
+		if err == ErrNoCrlsFound {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		for _, crl := range crls {
+			checker.AddCrl(crl)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return checker, failures, nil
+}
+
+func (checker *crlRevocationChecker) AddCrl(crl *x509.RevocationList) {
+	if crl == nil {
+		return
+	}
+
+	checker.mutex.Lock()
+	defer checker.mutex.Unlock()
+
+	checker.crls = append(checker.crls, crl)
+}
+
+func (checker *crlRevocationChecker) GetCrls() []*x509.RevocationList {
+	checker.mutex.RLock()
+	defer checker.mutex.RUnlock()
+
+	result := make([]*x509.RevocationList, len(checker.crls))
+	copy(result, checker.crls)
+	return result
+}
+
+func (checker *crlRevocationChecker) CheckRevocation(certificate *x509.Certificate, issuer *x509.Certificate) error {
+	if certificate == nil || issuer == nil {
+		return ErrRevocationStatusUnknown
+	}
+
+	crl := checker.findCrl(issuer)
+	if crl == nil {
+		return ErrRevocationStatusUnknown
+	}
+	for _, entry := range crl.RevokedCertificateEntries {
+		if entry.SerialNumber != nil && entry.SerialNumber.Cmp(certificate.SerialNumber) == 0 {
+			return ErrCertificateRevoked
+		}
+	}
+
+	return nil
+}
+
+func (checker *crlRevocationChecker) findCrl(issuer *x509.Certificate) *x509.RevocationList {
+	checker.mutex.RLock()
+	defer checker.mutex.RUnlock()
+
+	// Use the most recent CRL that is signed by the issuer and not past its next update
+	now := checker.clock.Now()
+	var result *x509.RevocationList
+	for _, crl := range checker.crls {
+		if !bytes.Equal(crl.RawIssuer, issuer.RawSubject) {
+			continue
+		}
+		if now.Before(crl.ThisUpdate) || (!crl.NextUpdate.IsZero() && now.After(crl.NextUpdate)) {
+			continue
+		}
+		if crl.CheckSignatureFrom(issuer) != nil {
+			continue
+		}
+		if result == nil || crl.ThisUpdate.After(result.ThisUpdate) {
+			result = crl
+		}
+	}
+	return result
+}
+
+func isCrlFile(name string) bool {
+	return hasFileExtension(name, crlFileExtensions)
+}
@@ -0,0 +1,163 @@
+package xmlsecurity
+
+import (
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func newTestCrl(t *testing.T, issuer *testCertificate, thisUpdate time.Time, revoked ...*x509.Certificate) *x509.RevocationList {
+	t.Helper()
+
+	entries := make([]x509.RevocationListEntry, 0)
+	for _, certificate := range revoked {
+		entries = append(entries, x509.RevocationListEntry{
+			SerialNumber:   certificate.SerialNumber,
+			RevocationTime: thisUpdate,
+		})
+	}
+
+	testSerialNumber++
+	der, err := x509.CreateRevocationList(rand.Reader, &x509.RevocationList{
+		Number:                    big.NewInt(testSerialNumber),
+		ThisUpdate:                thisUpdate,
+		NextUpdate:                thisUpdate.Add(24 * time.Hour),
+		RevokedCertificateEntries: entries,
+	}, issuer.Certificate, issuer.PrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	crl, err := x509.ParseRevocationList(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return crl
+}
+
+func Test_CrlRevocationChecker_CheckRevocation(t *testing.T) {
+	_, intermediate, leaf := newTestCertificateHierarchy(t, nil)
+	revokedLeaf := newTestCertificate(t, "Revoked Leaf", intermediate, nil)
+
+	now := time.Now().Truncate(time.Second)
+	clock := ClockFunc(func() time.Time { return now })
+	crl := newTestCrl(t, intermediate, now.Add(-time.Hour), revokedLeaf.Certificate)
+
+	// Create test case
+	testCase := []struct {
+		name        string
+		crls        []*x509.RevocationList
+		clock       Clock
+		certificate *x509.Certificate
+		expected    error
+	}{
+		{name: "good", crls: []*x509.RevocationList{crl}, clock: clock, certificate: leaf.Certificate, expected: nil},
+		{name: "revoked", crls: []*x509.RevocationList{crl}, clock: clock, certificate: revokedLeaf.Certificate, expected: ErrCertificateRevoked},
+		{name: "no crl", clock: clock, certificate: leaf.Certificate, expected: ErrRevocationStatusUnknown},
+		{
+			name:        "stale crl",
+			crls:        []*x509.RevocationList{crl},
+			clock:       ClockFunc(func() time.Time { return crl.NextUpdate.Add(time.Minute) }),
+			certificate: leaf.Certificate,
+			expected:    ErrRevocationStatusUnknown,
+		},
+		{
+			name:        "newer crl wins",
+			crls:        []*x509.RevocationList{crl, newTestCrl(t, intermediate, now.Add(-time.Minute))},
+			clock:       clock,
+			certificate: revokedLeaf.Certificate,
+			expected:    nil,
+		},
+	}
+
+	for _, tc := range testCase {
+		checker := NewCrlRevocationChecker(tc.clock, tc.crls...)
+
+		err := checker.CheckRevocation(tc.certificate, intermediate.Certificate)
+		if err != tc.expected {
+			t.Fatalf("%s: CrlRevocationChecker.CheckRevocation() = %v; want %v", tc.name, err, tc.expected)
+		}
+	}
+
+	// A CRL with the issuer name but signed by another key is ignored
+	forged := newTestCertificate(t, "Forged", nil, nil)
+	forgedCrl := newTestCrl(t, &testCertificate{Certificate: intermediate.Certificate, PrivateKey: forged.PrivateKey}, now.Add(-time.Minute))
+	checker := NewCrlRevocationChecker(clock, forgedCrl)
+	err := checker.CheckRevocation(leaf.Certificate, intermediate.Certificate)
+	if err != ErrRevocationStatusUnknown {
+		t.Fatalf("CrlRevocationChecker.CheckRevocation() with forged CRL = %v; want %v", err, ErrRevocationStatusUnknown)
+	}
+}
+
+func Test_CertificateValidator_Validate_Revoked(t *testing.T) {
+	root, intermediate, leaf := newTestCertificateHierarchy(t, nil)
+	now := time.Now()
+
+	// Create the test case validator
+	checker := NewCrlRevocationChecker(nil,
+		newTestCrl(t, root, now.Add(-time.Hour)),
+		newTestCrl(t, intermediate, now.Add(-time.Hour), leaf.Certificate),
+	)
+	validator := NewCertificateValidator(CertificateValidatorOptions{
+		Roots:              []*x509.Certificate{root.Certificate},
+		Intermediates:      []*x509.Certificate{intermediate.Certificate},
+		RevocationCheckers: []RevocationChecker{checker},
+	})
+
+	// Validate the test case token
+	token, err := NewX509BinarySecurityToken(nil, leaf.Certificate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = validator.ValidateProvider(nil, token)
+	var validationError *CertificateValidationError
+	if !errors.As(err, &validationError) || validationError.Reason != CertificateRevoked {
+		t.Fatalf("CertificateValidator.ValidateProvider() = %v; want %s", err, CertificateRevoked)
+	}
+	if !errors.Is(err, ErrCertificateRevoked) {
+		t.Fatal("CertificateValidationError does not wrap ErrCertificateRevoked")
+	}
+}
+
+func Test_LoadCrlRevocationCheckerFromFS(t *testing.T) {
+	_, intermediate, leaf := newTestCertificateHierarchy(t, nil)
+	crl := newTestCrl(t, intermediate, time.Now().Add(-time.Hour), leaf.Certificate)
+
+	// Create test case file system
+	fsys := fstest.MapFS{
+		"crl/intermediate.crl": {Data: crl.Raw},
+		"crl/intermediate.pem": {Data: pem.EncodeToMemory(&pem.Block{Type: "X509 CRL", Bytes: crl.Raw})},
+		"crl/broken.crl":       {Data: []byte("broken")},
+		"crl/leaf.pem":         {Data: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: leaf.Certificate.Raw})},
+	}
+
+	// Load the test case checker
+	checker, failures, err := LoadCrlRevocationCheckerFromFS(fsys, "crl", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(checker.GetCrls()) != 2 {
+		t.Fatalf("len(Crls) = %d; want 2", len(checker.GetCrls()))
+	}
+	if len(failures) != 1 || failures[0].Path != "crl/broken.crl" {
+		t.Fatalf("failures = %v; want crl/broken.crl", failures)
+	}
+
+	err = checker.CheckRevocation(leaf.Certificate, intermediate.Certificate)
+	if err != ErrCertificateRevoked {
+		t.Fatalf("CrlRevocationChecker.CheckRevocation() = %v; want %v", err, ErrCertificateRevoked)
+	}
+}
+
+func Test_ParseCrls_NoCrls(t *testing.T) {
+	leaf := newTestCertificate(t, "Test Leaf", nil, nil)
+
+	_, err := ParseCrls(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: leaf.Certificate.Raw}))
+	if err != ErrNoCrlsFound {
+		t.Fatalf("ParseCrls() = %v; want %v", err, ErrNoCrlsFound)
+	}
+}
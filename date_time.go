@@ -0,0 +1,40 @@
+package xmlsecurity
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidDateTime = errors.New("invalid xsd:dateTime")
+)
+
+const (
+	dateTimeFormat      = "2006-01-02T15:04:05.000Z07:00"
+	dateTimeLocalLayout = "2006-01-02T15:04:05.999999999"
+)
+
+func ParseDateTime(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, ErrInvalidDateTime
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err == nil {
+		return t, nil
+	}
+
+	// Values without a timezone are interpreted as UTC
+	t, err = time.Parse(dateTimeLocalLayout, value)
+	if err == nil {
+		return t, nil
+	}
+
+	return time.Time{}, ErrInvalidDateTime
+}
+
+func FormatDateTime(t time.Time) string {
+	return t.UTC().Format(dateTimeFormat)
+}
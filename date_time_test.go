@@ -0,0 +1,59 @@
+package xmlsecurity
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_ParseDateTime(t *testing.T) {
+	// Create test case
+	testCase := []struct {
+		value    string
+		expected time.Time
+		err      error
+	}{
+		{
+			value:    "2024-03-01T10:15:30Z",
+			expected: time.Date(2024, 3, 1, 10, 15, 30, 0, time.UTC),
+		},
+		{
+			value:    "2024-03-01T10:15:30.123456Z",
+			expected: time.Date(2024, 3, 1, 10, 15, 30, 123456000, time.UTC),
+		},
+		{
+			value:    "2024-03-01T12:15:30.5+02:00",
+			expected: time.Date(2024, 3, 1, 10, 15, 30, 500000000, time.UTC),
+		},
+		{
+			value:    "2024-03-01T10:15:30",
+			expected: time.Date(2024, 3, 1, 10, 15, 30, 0, time.UTC),
+		},
+		{
+			value: "2024-03-01",
+			err:   ErrInvalidDateTime,
+		},
+		{
+			value: "",
+			err:   ErrInvalidDateTime,
+		},
+	}
+
+	for _, tc := range testCase {
+		result, err := ParseDateTime(tc.value)
+		if err != tc.err {
+			t.Fatalf("ParseDateTime(%s) error = %v; want %v", tc.value, err, tc.err)
+		}
+		if !result.Equal(tc.expected) {
+			t.Fatalf("ParseDateTime(%s) = %s; want %s", tc.value, result, tc.expected)
+		}
+	}
+}
+
+func Test_FormatDateTime(t *testing.T) {
+	value := time.Date(2024, 3, 1, 12, 15, 30, 123456789, time.FixedZone("CEST", 2*60*60))
+
+	result := FormatDateTime(value)
+	if result != "2024-03-01T10:15:30.123Z" {
+		t.Fatalf("FormatDateTime() = %s; want 2024-03-01T10:15:30.123Z", result)
+	}
+}
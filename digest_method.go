@@ -0,0 +1,83 @@
+package xmlsecurity
+
+import (
+	"crypto"
+	_ "crypto/sha1"
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+	"errors"
+
+	"github.com/beevik/etree"
+	"github.com/deb-ict/go-xml"
+)
+
+const (
+	SHA1DigestAlgorithm   string = "http://www.w3.org/2000/09/xmldsig#sha1"
+	SHA256DigestAlgorithm string = "http://www.w3.org/2001/04/xmlenc#sha256"
+	SHA384DigestAlgorithm string = "http://www.w3.org/2001/04/xmldsig-more#sha384"
+	SHA512DigestAlgorithm string = "http://www.w3.org/2001/04/xmlenc#sha512"
+)
+
+var (
+	ErrUnsupportedDigestAlgorithm = errors.New("unsupported digest algorithm")
+)
+
+type DigestMethod interface {
+	xml.Node
+	GetAlgorithm() string
+	SetAlgorithm(algorithm string)
+}
+
+type digestMethod struct {
+	Algorithm string
+}
+
+func NewDigestMethod(context xml.Context) (DigestMethod, error) {
+	return &digestMethod{}, nil
+}
+
+func NewDigestMethodNode(context xml.Context) (xml.Node, error) {
+	return NewDigestMethod(context)
+}
+
+func (node *digestMethod) GetAlgorithm() string {
+	return node.Algorithm
+}
+
+func (node *digestMethod) SetAlgorithm(algorithm string) {
+	node.Algorithm = algorithm
+}
+
+func (node *digestMethod) LoadXml(context xml.Context, el *etree.Element) error {
+	err := xml.ValidateElement(el, "DigestMethod", DsigNamespace)
+	if err != nil {
+		return err
+	}
+
+	node.SetAlgorithm(el.SelectAttrValue("Algorithm", ""))
+
+	return nil
+}
+
+func (node *digestMethod) GetXml(context xml.Context) (*etree.Element, error) {
+	el := etree.NewElement("DigestMethod")
+	el.Space = context.GetNamespacePrefix(DsigNamespace)
+
+	el.CreateAttr("Algorithm", node.GetAlgorithm())
+
+	return el, nil
+}
+
+func getDigestHash(algorithm string) (crypto.Hash, error) {
+	switch algorithm {
+	case SHA1DigestAlgorithm:
+		return crypto.SHA1, nil
+	case SHA256DigestAlgorithm:
+		return crypto.SHA256, nil
+	case SHA384DigestAlgorithm:
+		return crypto.SHA384, nil
+	case SHA512DigestAlgorithm:
+		return crypto.SHA512, nil
+	}
+	return 0, ErrUnsupportedDigestAlgorithm
+}
@@ -0,0 +1,53 @@
+package xmlsecurity
+
+import (
+	"sort"
+
+	"github.com/beevik/etree"
+)
+
+func NewExcC14NCanonicalizer(withComments bool, inclusiveNamespaces ...string) Canonicalizer {
+	algorithm := ExcC14NAlgorithm
+	if withComments {
+		algorithm = ExcC14NWithCommentsAlgorithm
+	}
+
+	prefixes := make(map[string]bool)
+	for _, prefix := range inclusiveNamespaces {
+		if prefix == "#default" {
+			prefix = ""
+		}
+		prefixes[prefix] = true
+	}
+
+	return &canonicalizer{
+		algorithm:           algorithm,
+		withComments:        withComments,
+		mode:                excC14NMode,
+		inclusiveNamespaces: prefixes,
+	}
+}
+
+func (c *canonicalizer) exclusiveNamespaces(el *etree.Element, inScope map[string]string, rendered map[string]string) []string {
+	// Only namespaces visibly utilized by the element or listed as inclusive are rendered
+	utilized := map[string]bool{el.Space: true}
+	for _, attr := range el.Attr {
+		if attr.Space != "" && attr.Space != "xmlns" && attr.Space != "xml" {
+			utilized[attr.Space] = true
+		}
+	}
+	for prefix := range c.inclusiveNamespaces {
+		if _, found := inScope[prefix]; found {
+			utilized[prefix] = true
+		}
+	}
+
+	namespaces := make([]string, 0, len(utilized))
+	for prefix := range utilized {
+		if inScope[prefix] != rendered[prefix] {
+			namespaces = append(namespaces, prefix)
+		}
+	}
+	sort.Strings(namespaces)
+	return namespaces
+}
@@ -0,0 +1,181 @@
+package xmlsecurity
+
+import (
+	"testing"
+
+	"github.com/beevik/etree"
+)
+
+func Test_ExcC14NCanonicalizer_Canonicalize(t *testing.T) {
+	// Create test cases
+	testCases := []struct {
+		name                string
+		xml                 string
+		path                string
+		withComments        bool
+		inclusiveNamespaces []string
+		expected            string
+	}{
+		{
+			name: "spec example 1",
+			xml: "<n0:local xmlns:n0=\"foo:bar\" xmlns:n3=\"ftp://example.org\">\n" +
+				"  <n1:elem2 xmlns:n1=\"http://example.net\" xml:lang=\"en\">\n" +
+				"    <n3:stuff xmlns:n3=\"ftp://example.org\"/>\n" +
+				"  </n1:elem2>\n" +
+				"</n0:local>",
+			path: "//elem2",
+			expected: "<n1:elem2 xmlns:n1=\"http://example.net\" xml:lang=\"en\">\n" +
+				"    <n3:stuff xmlns:n3=\"ftp://example.org\"></n3:stuff>\n" +
+				"  </n1:elem2>",
+		},
+		{
+			name: "spec example 2",
+			xml: "<n2:pdu xmlns:n1=\"http://example.com\" xmlns:n2=\"http://foo.example\" xml:lang=\"fr\" xml:space=\"retain\">\n" +
+				"  <n1:elem2 xmlns:n1=\"http://example.net\" xml:lang=\"en\">\n" +
+				"    <n3:stuff xmlns:n3=\"ftp://example.org\"/>\n" +
+				"  </n1:elem2>\n" +
+				"</n2:pdu>",
+			path: "//elem2",
+			expected: "<n1:elem2 xmlns:n1=\"http://example.net\" xml:lang=\"en\">\n" +
+				"    <n3:stuff xmlns:n3=\"ftp://example.org\"></n3:stuff>\n" +
+				"  </n1:elem2>",
+		},
+		{
+			name:                "inclusive namespaces",
+			xml:                 `<n0:local xmlns:n0="foo:bar" xmlns:n3="ftp://example.org"><n1:elem2 xmlns:n1="http://example.net"><n3:stuff/></n1:elem2></n0:local>`,
+			path:                "//elem2",
+			inclusiveNamespaces: []string{"n0", "missing"},
+			expected:            `<n1:elem2 xmlns:n0="foo:bar" xmlns:n1="http://example.net"><n3:stuff xmlns:n3="ftp://example.org"></n3:stuff></n1:elem2>`,
+		},
+		{
+			name:                "inclusive default namespace",
+			xml:                 `<Envelope xmlns="urn:envelope" xmlns:u="urn:u"><Body u:Id="Body-1"><Payload/></Body></Envelope>`,
+			path:                "//Body",
+			inclusiveNamespaces: []string{"#default"},
+			expected:            `<Body xmlns="urn:envelope" xmlns:u="urn:u" u:Id="Body-1"><Payload></Payload></Body>`,
+		},
+		{
+			name:     "default namespace undeclaration",
+			xml:      `<a xmlns="urn:a"><b xmlns=""><c/></b></a>`,
+			path:     "//a",
+			expected: `<a xmlns="urn:a"><b xmlns=""><c></c></b></a>`,
+		},
+		{
+			name:     "empty default namespace subtree",
+			xml:      `<a xmlns="urn:a"><b xmlns=""><c/></b></a>`,
+			path:     "//b",
+			expected: `<b><c></c></b>`,
+		},
+		{
+			name:     "unused namespaces",
+			xml:      `<soap:Envelope xmlns:soap="urn:soap" xmlns:wsu="urn:wsu" xmlns:x="urn:x"><soap:Body wsu:Id="Body-1"><x:Payload xmlns:y="urn:y"/></soap:Body></soap:Envelope>`,
+			path:     "//Body",
+			expected: `<soap:Body xmlns:soap="urn:soap" xmlns:wsu="urn:wsu" wsu:Id="Body-1"><x:Payload xmlns:x="urn:x"></x:Payload></soap:Body>`,
+		},
+		{
+			name:     "attribute order and escaping",
+			xml:      `<e b="2" a="&quot;&#9;&#10;&#13;&lt;&amp;>" xmlns:z="urn:z" xmlns:y="urn:y" z:c="1" y:d="2"/>`,
+			path:     "//e",
+			expected: `<e xmlns:y="urn:y" xmlns:z="urn:z" a="&quot;&#x9;&#xA;&#xD;&lt;&amp;>" b="2" y:d="2" z:c="1"></e>`,
+		},
+		{
+			name:     "text escaping",
+			xml:      `<e>a &amp; b &lt; c > d&#13;<![CDATA[<cdata> & "quotes"]]></e>`,
+			path:     "//e",
+			expected: `<e>a &amp; b &lt; c &gt; d&#xD;&lt;cdata&gt; &amp; "quotes"</e>`,
+		},
+		{
+			name:     "comments removed",
+			xml:      `<e><!-- comment --><?pi data?><f/></e>`,
+			path:     "//e",
+			expected: `<e><?pi data?><f></f></e>`,
+		},
+		{
+			name:         "comments kept",
+			xml:          `<e><!-- comment --><?pi data?><f/></e>`,
+			path:         "//e",
+			withComments: true,
+			expected:     `<e><!-- comment --><?pi data?><f></f></e>`,
+		},
+	}
+
+	for _, tc := range testCases {
+		// Prepare the test case
+		testCaseDocument := etree.NewDocument()
+		err := testCaseDocument.ReadFromString(tc.xml)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Canonicalize the test case element
+		canonicalizer := NewExcC14NCanonicalizer(tc.withComments, tc.inclusiveNamespaces...)
+		result, err := canonicalizer.Canonicalize(testCaseDocument.FindElement(tc.path))
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if string(result) != tc.expected {
+			t.Fatalf("%s: ExcC14NCanonicalizer.Canonicalize() = %s; want %s", tc.name, result, tc.expected)
+		}
+	}
+}
+
+func Test_ExcC14NCanonicalizer_Canonicalize_UndeclaredPrefix(t *testing.T) {
+	// Create test case element
+	el := etree.NewElement("Body")
+	el.Space = "soap"
+
+	// Canonicalize the test case element
+	_, err := NewExcC14NCanonicalizer(false).Canonicalize(el)
+	if err != ErrUndeclaredNamespacePrefix {
+		t.Fatalf("ExcC14NCanonicalizer.Canonicalize() error = %v; want %v", err, ErrUndeclaredNamespacePrefix)
+	}
+}
+
+func Test_NewCanonicalizer(t *testing.T) {
+	// Create test cases
+	testCases := []struct {
+		algorithm string
+		err       error
+	}{
+		{
+			algorithm: ExcC14NAlgorithm,
+		},
+		{
+			algorithm: ExcC14NWithCommentsAlgorithm,
+		},
+		{
+			algorithm: C14N10Algorithm,
+		},
+		{
+			algorithm: C14N10WithCommentsAlgorithm,
+		},
+		{
+			algorithm: C14N11Algorithm,
+		},
+		{
+			algorithm: C14N11WithCommentsAlgorithm,
+		},
+		{
+			algorithm: "urn:unknown",
+			err:       ErrUnsupportedCanonicalizationAlgorithm,
+		},
+	}
+
+	for _, tc := range testCases {
+		// Create test case Transform
+		testCaseTransform, err := NewTransform(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		testCaseTransform.SetAlgorithm(tc.algorithm)
+
+		// Get test case Canonicalizer
+		canonicalizer, err := testCaseTransform.GetCanonicalizer()
+		if err != tc.err {
+			t.Fatalf("Transform.GetCanonicalizer() error = %v; want %v", err, tc.err)
+		}
+		if err == nil && canonicalizer.GetAlgorithm() != tc.algorithm {
+			t.Fatalf("Canonicalizer.Algorithm = %s; want %s", canonicalizer.GetAlgorithm(), tc.algorithm)
+		}
+	}
+}
@@ -7,4 +7,6 @@ toolchain go1.23.6
 require (
 	github.com/beevik/etree v1.5.0
 	github.com/deb-ict/go-xml v0.0.2-alpha
+	golang.org/x/crypto v0.11.0
+	software.sslmate.com/src/go-pkcs12 v0.7.3
 )
@@ -0,0 +1,67 @@
+package xmlsecurity
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"testing"
+	"time"
+)
+
+type testCertificate struct {
+	Certificate *x509.Certificate
+	PrivateKey  crypto.Signer
+}
+
+var testSerialNumber int64 = 1000
+
+func newTestCertificate(t *testing.T, commonName string, issuer *testCertificate, modify func(template *x509.Certificate)) *testCertificate {
+	t.Helper()
+
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testSerialNumber++
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(testSerialNumber),
+		Subject:               pkix.Name{CommonName: commonName, Organization: []string{"deb-ict"}},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+	}
+	if issuer == nil {
+		template.IsCA = true
+		template.KeyUsage |= x509.KeyUsageCertSign | x509.KeyUsageCRLSign
+	}
+	if modify != nil {
+		modify(template)
+	}
+
+	parent := template
+	var signer crypto.Signer = privateKey
+	if issuer != nil {
+		parent = issuer.Certificate
+		signer = issuer.PrivateKey
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, privateKey.Public(), signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certificate, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &testCertificate{
+		Certificate: certificate,
+		PrivateKey:  privateKey,
+	}
+}
@@ -0,0 +1,159 @@
+package xmlsecurity
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"sync"
+	"unicode"
+
+	"github.com/deb-ict/go-xml"
+)
+
+const (
+	DefaultIdPrefix string = "id"
+)
+
+var (
+	ErrInvalidIdPrefix = errors.New("id prefix is not a valid NCName")
+	ErrIdCollision     = errors.New("unable to generate a unique id")
+)
+
+type IdGenerator interface {
+	GetPrefix(tag string) string
+	SetPrefix(tag string, prefix string) error
+	SetDefaultPrefix(prefix string) error
+	GenerateId(context xml.Context, tag string) (string, error)
+}
+
+type idGenerator struct {
+	mu            sync.Mutex
+	defaultPrefix string
+	prefixes      map[string]string
+	issued        map[string]bool
+}
+
+func NewIdGenerator() IdGenerator {
+	return &idGenerator{
+		defaultPrefix: DefaultIdPrefix,
+		prefixes: map[string]string{
+			"BinarySecurityToken":    "X509",
+			"Body":                   "Body",
+			"SecurityTokenReference": "STR",
+			"Timestamp":              "TS",
+			"UsernameToken":          "UsernameToken",
+		},
+		issued: make(map[string]bool),
+	}
+}
+
+func GetIdGenerator(context xml.Context) IdGenerator {
+	securityContext, ok := context.(Context)
+	if !ok || securityContext.GetIdGenerator() == nil {
+		return NewIdGenerator()
+	}
+	return securityContext.GetIdGenerator()
+}
+
+func (generator *idGenerator) GetPrefix(tag string) string {
+	generator.mu.Lock()
+	defer generator.mu.Unlock()
+
+	prefix, found := generator.prefixes[tag]
+	if !found {
+		return generator.defaultPrefix
+	}
+	return prefix
+}
+
+func (generator *idGenerator) SetPrefix(tag string, prefix string) error {
+	if !isNCName(prefix) {
+		return ErrInvalidIdPrefix
+	}
+
+	generator.mu.Lock()
+	defer generator.mu.Unlock()
+
+	generator.prefixes[tag] = prefix
+	return nil
+}
+
+func (generator *idGenerator) SetDefaultPrefix(prefix string) error {
+	if !isNCName(prefix) {
+		return ErrInvalidIdPrefix
+	}
+
+	generator.mu.Lock()
+	defer generator.mu.Unlock()
+
+	generator.defaultPrefix = prefix
+	return nil
+}
+
+func (generator *idGenerator) GenerateId(context xml.Context, tag string) (string, error) {
+	prefix := generator.GetPrefix(tag)
+
+	// Ids already present in the document must not be reused
+	var index IdIndex
+	if context != nil && context.GetDocument() != nil {
+		var err error
+		index, err = GetIdIndex(context)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	generator.mu.Lock()
+	defer generator.mu.Unlock()
+
+	value := make([]byte, 16)
+	for attempt := 0; attempt < 8; attempt++ {
+		_, err := rand.Read(value)
+		if err != nil {
+			return "", err
+		}
+		id := prefix + "-" + hex.EncodeToString(value)
+		if generator.issued[id] || (index != nil && index.Contains(id)) {
+			continue
+		}
+		generator.issued[id] = true
+		return id, nil
+	}
+	return "", ErrIdCollision
+}
+
+func isNCName(value string) bool {
+	if value == "" {
+		return false
+	}
+	for i, r := range value {
+		switch {
+		case unicode.IsLetter(r) || r == '_':
+		case i > 0 && (unicode.IsDigit(r) || r == '-' || r == '.'):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func generateMissingId(context xml.Context, node interface {
+	GetId() string
+	SetId(id string)
+}, tag string) error {
+	if node.GetId() != "" {
+		return nil
+	}
+
+	// Only contexts with an attached generator opt in to generated ids
+	securityContext, ok := context.(Context)
+	if !ok || securityContext.GetIdGenerator() == nil {
+		return nil
+	}
+	id, err := securityContext.GetIdGenerator().GenerateId(context, tag)
+	if err != nil {
+		return err
+	}
+	node.SetId(id)
+	return nil
+}
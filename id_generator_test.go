@@ -0,0 +1,143 @@
+package xmlsecurity
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/beevik/etree"
+	"github.com/deb-ict/go-xml"
+)
+
+func Test_IdGenerator_GenerateId(t *testing.T) {
+	testCaseContext := NewContext(xml.NewContext(etree.NewDocument()))
+
+	// Create test case IdGenerator
+	testCaseIdGenerator := NewIdGenerator()
+	err := testCaseIdGenerator.SetPrefix("Body", "Body")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Create test cases
+	testCases := []struct {
+		tag    string
+		prefix string
+	}{
+		{
+			tag:    "BinarySecurityToken",
+			prefix: "X509-",
+		},
+		{
+			tag:    "Timestamp",
+			prefix: "TS-",
+		},
+		{
+			tag:    "Body",
+			prefix: "Body-",
+		},
+		{
+			tag:    "Unknown",
+			prefix: DefaultIdPrefix + "-",
+		},
+	}
+
+	issued := make(map[string]bool)
+	for _, tc := range testCases {
+		// Generate test case id
+		id, err := testCaseIdGenerator.GenerateId(testCaseContext, tc.tag)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasPrefix(id, tc.prefix) {
+			t.Fatalf("IdGenerator.GenerateId(%s) = %s; want %s prefix", tc.tag, id, tc.prefix)
+		}
+		if !isNCName(id) {
+			t.Fatalf("IdGenerator.GenerateId(%s) = %s; not a valid NCName", tc.tag, id)
+		}
+		if issued[id] {
+			t.Fatalf("IdGenerator.GenerateId(%s) returned %s twice", tc.tag, id)
+		}
+		issued[id] = true
+	}
+}
+
+func Test_IdGenerator_SetPrefix_Invalid(t *testing.T) {
+	// Create test cases
+	testCases := []string{
+		"",
+		"1abc",
+		"-abc",
+		"a:b",
+		"a b",
+	}
+
+	testCaseIdGenerator := NewIdGenerator()
+	for _, tc := range testCases {
+		// Set test case prefix
+		err := testCaseIdGenerator.SetPrefix("Body", tc)
+		if err != ErrInvalidIdPrefix {
+			t.Fatalf("IdGenerator.SetPrefix(%s) error = %v; want %v", tc, err, ErrInvalidIdPrefix)
+		}
+		err = testCaseIdGenerator.SetDefaultPrefix(tc)
+		if err != ErrInvalidIdPrefix {
+			t.Fatalf("IdGenerator.SetDefaultPrefix(%s) error = %v; want %v", tc, err, ErrInvalidIdPrefix)
+		}
+	}
+}
+
+func Test_IdGenerator_GenerateId_DuplicateDocument(t *testing.T) {
+	// Prepare the test case
+	testCaseDocument := etree.NewDocument()
+	err := testCaseDocument.ReadFromString(`<Envelope xmlns:wsu="` + WsuNamespace + `"><Body wsu:Id="A"/><Body wsu:Id="A"/></Envelope>`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCaseContext := xml.NewContext(testCaseDocument)
+
+	// Generate test case id
+	_, err = NewIdGenerator().GenerateId(testCaseContext, "Body")
+	if err == nil {
+		t.Fatal("IdGenerator.GenerateId() succeeded on a document with duplicate ids")
+	}
+}
+
+func Test_IdGenerator_GetXml(t *testing.T) {
+	testCaseContext := NewContext(xml.NewContext(etree.NewDocument()))
+	ConfigureContext(testCaseContext)
+
+	// Create test case Timestamp
+	testCaseTimestamp, err := NewTimestamp(testCaseContext)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Without a generator the Id stays empty
+	el, err := testCaseTimestamp.GetXml(testCaseContext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if GetWsuId(testCaseContext, el) != "" || testCaseTimestamp.GetId() != "" {
+		t.Fatal("Timestamp.GetXml() generated an Id without a generator")
+	}
+
+	// With a generator the Id is assigned once
+	testCaseContext.SetIdGenerator(NewIdGenerator())
+	el, err = testCaseTimestamp.GetXml(testCaseContext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := testCaseTimestamp.GetId()
+	if !strings.HasPrefix(id, "TS-") {
+		t.Fatalf("Timestamp.Id = %s; want TS- prefix", id)
+	}
+	if GetWsuId(testCaseContext, el) != id {
+		t.Fatalf("Timestamp wsu:Id = %s; want %s", GetWsuId(testCaseContext, el), id)
+	}
+	el, err = testCaseTimestamp.GetXml(testCaseContext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if GetWsuId(testCaseContext, el) != id {
+		t.Fatal("Timestamp.GetXml() regenerated an existing Id")
+	}
+}
@@ -0,0 +1,108 @@
+package xmlsecurity
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/beevik/etree"
+	"github.com/deb-ict/go-xml"
+)
+
+var (
+	ErrDuplicateId = errors.New("duplicate id")
+)
+
+type IdIndex interface {
+	GetIds() []string
+	Contains(id string) bool
+	GetElement(id string) (*etree.Element, error)
+	ResolveUri(uri string) (*etree.Element, error)
+}
+
+type idIndex struct {
+	ids      []string
+	elements map[string]*etree.Element
+}
+
+func NewIdIndex(context xml.Context, resolver IdResolver) (IdIndex, error) {
+	index := &idIndex{
+		ids:      make([]string, 0),
+		elements: make(map[string]*etree.Element),
+	}
+
+	for _, el := range context.GetDocument().ChildElements() {
+		err := index.addElement(context, resolver, el)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return index, nil
+}
+
+func GetIdIndex(context xml.Context) (IdIndex, error) {
+	securityContext, ok := context.(Context)
+	if !ok {
+		return NewIdIndex(context, GetIdResolver(context))
+	}
+	return securityContext.GetIdIndex()
+}
+
+func retainIdIndex(context xml.Context) func() {
+	securityContext, ok := context.(Context)
+	if !ok {
+		return func() {}
+	}
+	securityContext.RetainIdIndex()
+	return securityContext.ReleaseIdIndex
+}
+
+func (index *idIndex) GetIds() []string {
+	return index.ids
+}
+
+func (index *idIndex) Contains(id string) bool {
+	_, found := index.elements[id]
+	return found
+}
+
+func (index *idIndex) GetElement(id string) (*etree.Element, error) {
+	el, found := index.elements[id]
+	if !found {
+		return nil, ErrIdNotFound
+	}
+	return el, nil
+}
+
+func (index *idIndex) ResolveUri(uri string) (*etree.Element, error) {
+	if !strings.HasPrefix(uri, "#") {
+		return nil, ErrUnsupportedUri
+	}
+	return index.GetElement(uri[1:])
+}
+
+func (index *idIndex) addElement(context xml.Context, resolver IdResolver, el *etree.Element) error {
+	for _, attribute := range resolver.GetIdAttributes() {
+		attr := attribute.selectAttr(context, el)
+		if attr == nil || attr.Value == "" {
+			continue
+		}
+		existing, found := index.elements[attr.Value]
+		if found && existing != el {
+			return fmt.Errorf("%w: %s", ErrDuplicateId, attr.Value)
+		}
+		if !found {
+			index.ids = append(index.ids, attr.Value)
+			index.elements[attr.Value] = el
+		}
+	}
+
+	for _, child := range el.ChildElements() {
+		err := index.addElement(context, resolver, child)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package xmlsecurity
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/beevik/etree"
+	"github.com/deb-ict/go-xml"
+)
+
+func Test_NewIdIndex(t *testing.T) {
+	// Create test case XML
+	testCaseXml := fmt.Sprintf(
+		`<Envelope xmlns:wsu="%s">`+
+			`<Header><Timestamp wsu:Id="TS-1"/><Token wsu:Id="BST-1" Id="BST-1"/></Header>`+
+			`<Body wsu:Id="Body-1"/>`+
+			`</Envelope>`,
+		WsuNamespace,
+	)
+
+	// Prepare the test case
+	testCaseDocument := etree.NewDocument()
+	err := testCaseDocument.ReadFromString(testCaseXml)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCaseContext := xml.NewContext(testCaseDocument)
+
+	// Create test case IdIndex
+	testCaseIdIndex, err := NewIdIndex(testCaseContext, NewIdResolver(UnqualifiedIdAttribute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Validate test case IdIndex
+	ids := testCaseIdIndex.GetIds()
+	if len(ids) != 3 || ids[0] != "TS-1" || ids[1] != "BST-1" || ids[2] != "Body-1" {
+		t.Fatalf("IdIndex.GetIds() = %v; want [TS-1 BST-1 Body-1]", ids)
+	}
+	if testCaseIdIndex.Contains("missing") {
+		t.Fatal("IdIndex.Contains(missing) = true; want false")
+	}
+	el, err := testCaseIdIndex.ResolveUri("#Body-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if el.Tag != "Body" {
+		t.Fatalf("IdIndex.ResolveUri(#Body-1) = %s; want Body", el.Tag)
+	}
+}
+
+func Test_NewIdIndex_DuplicateId(t *testing.T) {
+	// Create test cases
+	testCases := []string{
+		`<Envelope xmlns:wsu="` + WsuNamespace + `"><Body wsu:Id="Body-1"/><Body wsu:Id="Body-1"/></Envelope>`,
+		`<Envelope xmlns:wsu="` + WsuNamespace + `" xmlns:u="` + WsuNamespace + `"><Body wsu:Id="Body-1"><Body u:Id="Body-1"/></Body></Envelope>`,
+		`<Envelope xmlns:wsu="` + WsuNamespace + `"><Body wsu:Id="Body-1"/><Wrapper Id="Body-1"/></Envelope>`,
+	}
+
+	for _, tc := range testCases {
+		// Prepare the test case
+		testCaseDocument := etree.NewDocument()
+		err := testCaseDocument.ReadFromString(tc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		testCaseContext := xml.NewContext(testCaseDocument)
+
+		// Create test case IdIndex
+		_, err = NewIdIndex(testCaseContext, NewIdResolver(UnqualifiedIdAttribute))
+		if !errors.Is(err, ErrDuplicateId) {
+			t.Fatalf("NewIdIndex() error = %v; want %v", err, ErrDuplicateId)
+		}
+	}
+}
+
+func Test_GetIdIndex(t *testing.T) {
+	// Prepare the test case
+	testCaseDocument := etree.NewDocument()
+	err := testCaseDocument.ReadFromString(`<Envelope xmlns:wsu="` + WsuNamespace + `"><Body wsu:Id="Body-1"/></Envelope>`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCaseContext := NewContext(xml.NewContext(testCaseDocument))
+
+	// Get test case IdIndex while it is retained
+	release := retainIdIndex(testCaseContext)
+	testCaseIdIndex, err := GetIdIndex(testCaseContext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cachedIdIndex, err := GetIdIndex(testCaseContext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cachedIdIndex != testCaseIdIndex {
+		t.Fatal("GetIdIndex() did not reuse the retained index")
+	}
+
+	// Reset test case IdIndex after a document change
+	testCaseDocument.Root().CreateElement("Timestamp").CreateAttr("wsu:Id", "TS-1")
+	testCaseContext.ResetIdIndex()
+	testCaseIdIndex, err = GetIdIndex(testCaseContext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !testCaseIdIndex.Contains("TS-1") {
+		t.Fatal("IdIndex.Contains(TS-1) = false after ResetIdIndex(); want true")
+	}
+
+	// Get test case IdIndex after a document change without a retained index
+	release()
+	testCaseDocument.Root().CreateElement("Header").CreateAttr("wsu:Id", "Header-1")
+	testCaseIdIndex, err = GetIdIndex(testCaseContext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !testCaseIdIndex.Contains("Header-1") {
+		t.Fatal("IdIndex.Contains(Header-1) = false after release; want true")
+	}
+}
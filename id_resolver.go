@@ -0,0 +1,129 @@
+package xmlsecurity
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/beevik/etree"
+	"github.com/deb-ict/go-xml"
+)
+
+var (
+	ErrIdNotFound     = errors.New("id not found")
+	ErrIdAmbiguous    = errors.New("id is ambiguous")
+	ErrUnsupportedUri = errors.New("unsupported URI format")
+)
+
+type IdAttribute struct {
+	NamespaceUri string
+	Name         string
+}
+
+var (
+	WsuIdAttribute         = IdAttribute{NamespaceUri: WsuNamespace, Name: "Id"}
+	XmlIdAttribute         = IdAttribute{NamespaceUri: XmlNamespace, Name: "id"}
+	UnqualifiedIdAttribute = IdAttribute{Name: "Id"}
+)
+
+type IdResolver interface {
+	GetIdAttributes() []IdAttribute
+	AddIdAttribute(attribute IdAttribute)
+	GetElementId(context xml.Context, el *etree.Element) string
+	ResolveId(context xml.Context, id string) (*etree.Element, error)
+	ResolveUri(context xml.Context, uri string) (*etree.Element, error)
+}
+
+type idResolver struct {
+	attributes []IdAttribute
+}
+
+func NewIdResolver(attributes ...IdAttribute) IdResolver {
+	resolver := &idResolver{
+		attributes: []IdAttribute{WsuIdAttribute},
+	}
+	for _, attribute := range attributes {
+		resolver.AddIdAttribute(attribute)
+	}
+	return resolver
+}
+
+func GetIdResolver(context xml.Context) IdResolver {
+	securityContext, ok := context.(Context)
+	if !ok || securityContext.GetIdResolver() == nil {
+		return NewIdResolver()
+	}
+	return securityContext.GetIdResolver()
+}
+
+func (resolver *idResolver) GetIdAttributes() []IdAttribute {
+	return resolver.attributes
+}
+
+func (resolver *idResolver) AddIdAttribute(attribute IdAttribute) {
+	for _, existing := range resolver.attributes {
+		if existing == attribute {
+			return
+		}
+	}
+	resolver.attributes = append(resolver.attributes, attribute)
+}
+
+func (resolver *idResolver) GetElementId(context xml.Context, el *etree.Element) string {
+	for _, attribute := range resolver.attributes {
+		if attr := attribute.selectAttr(context, el); attr != nil {
+			return attr.Value
+		}
+	}
+	return ""
+}
+
+func (resolver *idResolver) ResolveId(context xml.Context, id string) (*etree.Element, error) {
+	index, err := resolver.getIdIndex(context)
+	if err != nil {
+		return nil, err
+	}
+	return index.GetElement(id)
+}
+
+func (resolver *idResolver) ResolveUri(context xml.Context, uri string) (*etree.Element, error) {
+	index, err := resolver.getIdIndex(context)
+	if err != nil {
+		return nil, err
+	}
+	return index.ResolveUri(uri)
+}
+
+func (resolver *idResolver) getIdIndex(context xml.Context) (IdIndex, error) {
+	// Reuse the cached index when this resolver is the one attached to the context
+	var index IdIndex
+	var err error
+	securityContext, ok := context.(Context)
+	if ok && securityContext.GetIdResolver() == IdResolver(resolver) {
+		index, err = securityContext.GetIdIndex()
+	} else {
+		index, err = NewIdIndex(context, resolver)
+	}
+	if errors.Is(err, ErrDuplicateId) {
+		return nil, fmt.Errorf("%w: %w", ErrIdAmbiguous, err)
+	}
+	return index, err
+}
+
+func (attribute IdAttribute) selectAttr(context xml.Context, el *etree.Element) *etree.Attr {
+	for i := range el.Attr {
+		attr := &el.Attr[i]
+		if attr.Key != attribute.Name || attr.Space == "xmlns" {
+			continue
+		}
+		if attribute.NamespaceUri == "" {
+			if attr.Space == "" {
+				return attr
+			}
+			continue
+		}
+		if attr.Space != "" && attrNamespaceUri(context, el, attr) == attribute.NamespaceUri {
+			return attr
+		}
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+package xmlsecurity
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/beevik/etree"
+	"github.com/deb-ict/go-xml"
+)
+
+func Test_IdResolver_ResolveUri(t *testing.T) {
+	// Create test case XML
+	testCaseXml := fmt.Sprintf(
+		`<Root xmlns:wsu="%s" xmlns:other="urn:other">`+
+			`<Token other:Id="A"/>`+
+			`<Token Id="A"/>`+
+			`<Token xml:id="A"/>`+
+			`<Token wsu:Id="A" Name="wsu"/>`+
+			`<Token wsu:Id="x']|//*[@Id='A"/>`+
+			`</Root>`,
+		WsuNamespace,
+	)
+
+	// Prepare the test case
+	testCaseDocument := etree.NewDocument()
+	err := testCaseDocument.ReadFromString(testCaseXml)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCaseContext := xml.NewContext(testCaseDocument)
+
+	// Resolve test case URI by wsu:Id
+	resolver := NewIdResolver()
+	el, err := resolver.ResolveUri(testCaseContext, "#A")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if el.SelectAttrValue("Name", "") != "wsu" {
+		t.Fatal("IdResolver.ResolveUri() did not return the wsu:Id element")
+	}
+
+	// Resolve test case URI containing quotes
+	el, err = resolver.ResolveUri(testCaseContext, "#x']|//*[@Id='A")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if GetWsuId(testCaseContext, el) != "x']|//*[@Id='A" {
+		t.Fatal("IdResolver.ResolveUri() did not match the literal id")
+	}
+
+	// Resolve test case URI with extra id attributes
+	resolver.AddIdAttribute(UnqualifiedIdAttribute)
+	_, err = resolver.ResolveUri(testCaseContext, "#A")
+	if !errors.Is(err, ErrIdAmbiguous) || !errors.Is(err, ErrDuplicateId) {
+		t.Fatalf("IdResolver.ResolveUri() error = %v; want %v", err, ErrIdAmbiguous)
+	}
+}
+
+func Test_IdResolver_ResolveUri_Errors(t *testing.T) {
+	// Create test cases
+	testCases := []struct {
+		uri string
+		err error
+	}{
+		{
+			uri: "#missing",
+			err: ErrIdNotFound,
+		},
+		{
+			uri: "#",
+			err: ErrIdNotFound,
+		},
+		{
+			uri: "A",
+			err: ErrUnsupportedUri,
+		},
+	}
+
+	// Prepare the test case
+	testCaseDocument := etree.NewDocument()
+	err := testCaseDocument.ReadFromString(`<Root xmlns:wsu="` + WsuNamespace + `"><A wsu:Id="A"/></Root>`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCaseContext := xml.NewContext(testCaseDocument)
+
+	for _, tc := range testCases {
+		// Resolve test case URI
+		_, err := NewIdResolver().ResolveUri(testCaseContext, tc.uri)
+		if err != tc.err {
+			t.Fatalf("IdResolver.ResolveUri(%s) error = %v; want %v", tc.uri, err, tc.err)
+		}
+	}
+}
+
+func Test_IdResolver_GetElementId(t *testing.T) {
+	// Create test cases
+	testCases := []struct {
+		xml string
+		id  string
+	}{
+		{
+			xml: `<Test xmlns:wsu="` + WsuNamespace + `" wsu:Id="wsu" Id="plain" xml:id="xml"/>`,
+			id:  "wsu",
+		},
+		{
+			xml: `<Test Id="plain" xml:id="xml"/>`,
+			id:  "xml",
+		},
+		{
+			xml: `<Test Id="plain"/>`,
+			id:  "plain",
+		},
+		{
+			xml: `<Test xmlns:other="urn:other" other:Id="other"/>`,
+			id:  "",
+		},
+	}
+
+	resolver := NewIdResolver(XmlIdAttribute, UnqualifiedIdAttribute)
+	for _, tc := range testCases {
+		// Prepare the test case
+		testCaseDocument := etree.NewDocument()
+		err := testCaseDocument.ReadFromString(tc.xml)
+		if err != nil {
+			t.Fatal(err)
+		}
+		testCaseContext := xml.NewContext(testCaseDocument)
+
+		// Get test case element id
+		id := resolver.GetElementId(testCaseContext, testCaseDocument.Root())
+		if id != tc.id {
+			t.Fatalf("IdResolver.GetElementId() = %s; want %s", id, tc.id)
+		}
+	}
+}
+
+func Test_GetIdResolver(t *testing.T) {
+	testCaseContext := NewContext(xml.NewContext(etree.NewDocument()))
+
+	// Default test case resolver
+	attributes := GetIdResolver(testCaseContext).GetIdAttributes()
+	if len(attributes) != 1 || attributes[0] != WsuIdAttribute {
+		t.Fatal("GetIdResolver() did not default to wsu:Id")
+	}
+
+	// Attach a test case resolver
+	resolver := NewIdResolver(XmlIdAttribute)
+	testCaseContext.SetIdResolver(resolver)
+	if GetIdResolver(testCaseContext) != resolver {
+		t.Fatal("GetIdResolver() did not return the attached resolver")
+	}
+}
@@ -0,0 +1,180 @@
+package xmlsecurity
+
+import (
+	"crypto/sha1"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+
+	"github.com/beevik/etree"
+	"github.com/deb-ict/go-xml"
+)
+
+const (
+	ThumbprintSHA1ValueType           string = "http://docs.oasis-open.org/wss/oasis-wss-soap-message-security-1.1#ThumbprintSHA1"
+	X509SubjectKeyIdentifierValueType string = "http://docs.oasis-open.org/wss/2004/01/oasis-200401-wss-x509-token-profile-1.0#X509SubjectKeyIdentifier"
+)
+
+var (
+	ErrUnsupportedValueType = errors.New("unsupported value type")
+)
+
+type KeyIdentifier interface {
+	xml.Node
+	X509CertificateProvider
+	X509ChainProvider
+	GetId() string
+	SetId(id string)
+	GetValueType() string
+	SetValueType(valueType string)
+	GetEncodingType() string
+	SetEncodingType(encodingType string)
+	GetValue() string
+	SetValue(value string)
+	GetValueBytes() ([]byte, error)
+}
+
+type keyIdentifier struct {
+	Id           string
+	ValueType    string
+	EncodingType string
+	Value        string
+}
+
+func NewKeyIdentifier(context xml.Context) (KeyIdentifier, error) {
+	return &keyIdentifier{}, nil
+}
+
+func NewKeyIdentifierNode(context xml.Context) (xml.Node, error) {
+	return NewKeyIdentifier(context)
+}
+
+func NewX509KeyIdentifier(context xml.Context, cert *x509.Certificate, valueType string) (KeyIdentifier, error) {
+	var value []byte
+	switch valueType {
+	case ThumbprintSHA1ValueType:
+		thumbprint := sha1.Sum(cert.Raw)
+		value = thumbprint[:]
+	case X509SubjectKeyIdentifierValueType:
+		if len(cert.SubjectKeyId) == 0 {
+			return nil, errors.New("certificate has no subject key identifier")
+		}
+		value = cert.SubjectKeyId
+	case X509v3ValueType:
+		value = cert.Raw
+	default:
+		return nil, ErrUnsupportedValueType
+	}
+
+	return &keyIdentifier{
+		ValueType:    valueType,
+		EncodingType: Base64BinaryEncodingType,
+		Value:        base64.StdEncoding.EncodeToString(value),
+	}, nil
+}
+
+func (node *keyIdentifier) GetId() string {
+	return node.Id
+}
+
+func (node *keyIdentifier) SetId(id string) {
+	node.Id = id
+}
+
+func (node *keyIdentifier) GetValueType() string {
+	return node.ValueType
+}
+
+func (node *keyIdentifier) SetValueType(valueType string) {
+	node.ValueType = valueType
+}
+
+func (node *keyIdentifier) GetEncodingType() string {
+	return node.EncodingType
+}
+
+func (node *keyIdentifier) SetEncodingType(encodingType string) {
+	node.EncodingType = encodingType
+}
+
+func (node *keyIdentifier) GetValue() string {
+	return node.Value
+}
+
+func (node *keyIdentifier) SetValue(value string) {
+	node.Value = value
+}
+
+func (node *keyIdentifier) GetValueBytes() ([]byte, error) {
+	// Base64Binary is the default encoding for key identifiers
+	if node.GetEncodingType() != "" && node.GetEncodingType() != Base64BinaryEncodingType {
+		return nil, ErrUnsupportedEncodingType
+	}
+	return base64.StdEncoding.DecodeString(node.GetValue())
+}
+
+func (node *keyIdentifier) GetX509Certificate(context xml.Context) (*x509.Certificate, error) {
+	value, err := node.GetValueBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	switch node.GetValueType() {
+	case X509v3ValueType:
+		return x509.ParseCertificate(value)
+	case ThumbprintSHA1ValueType:
+		store := GetCertificateStore(context)
+		if store == nil {
+			return nil, ErrX509CertificateNotAvailable
+		}
+		return store.FindByThumbprintSHA1(value)
+	case X509SubjectKeyIdentifierValueType:
+		store := GetCertificateStore(context)
+		if store == nil {
+			return nil, ErrX509CertificateNotAvailable
+		}
+		return store.FindBySubjectKeyId(value)
+	default:
+		return nil, ErrUnsupportedValueType
+	}
+}
+
+func (node *keyIdentifier) GetX509CertificateChain(context xml.Context) ([]*x509.Certificate, error) {
+	certificate, err := node.GetX509Certificate(context)
+	if err != nil {
+		return nil, err
+	}
+	return []*x509.Certificate{certificate}, nil
+}
+
+func (node *keyIdentifier) LoadXml(context xml.Context, el *etree.Element) error {
+	err := xml.ValidateElement(el, "KeyIdentifier", WsseNamespace)
+	if err != nil {
+		return err
+	}
+
+	node.SetId(GetWsuId(context, el))
+	node.SetValueType(el.SelectAttrValue("ValueType", ""))
+	node.SetEncodingType(el.SelectAttrValue("EncodingType", ""))
+	node.SetValue(el.Text())
+
+	return nil
+}
+
+func (node *keyIdentifier) GetXml(context xml.Context) (*etree.Element, error) {
+	el := etree.NewElement("KeyIdentifier")
+	el.Space = context.GetNamespacePrefix(WsseNamespace)
+
+	if node.GetId() != "" {
+		SetWsuId(context, el, node.GetId())
+	}
+	if node.GetValueType() != "" {
+		el.CreateAttr("ValueType", node.GetValueType())
+	}
+	if node.GetEncodingType() != "" {
+		el.CreateAttr("EncodingType", node.GetEncodingType())
+	}
+	el.SetText(node.GetValue())
+
+	return el, nil
+}
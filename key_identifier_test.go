@@ -0,0 +1,230 @@
+package xmlsecurity
+
+import (
+	"crypto/sha1"
+	"encoding/base64"
+	"fmt"
+	"testing"
+
+	"github.com/beevik/etree"
+	"github.com/deb-ict/go-xml"
+)
+
+func Test_KeyIdentifier_LoadXml_InvalidElement(t *testing.T) {
+	// Create test case XML
+	testCaseXml := fmt.Sprintf(
+		`<wsse:InvalidTag xmlns:wsse="%s"/>`,
+		WsseNamespace,
+	)
+
+	// Prepare the test case
+	testCaseDocument := etree.NewDocument()
+	err := testCaseDocument.ReadFromString(testCaseXml)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCaseContext := xml.NewContext(testCaseDocument)
+
+	// Create test case KeyIdentifier
+	testCaseKeyIdentifier, err := NewKeyIdentifier(testCaseContext)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Load test case KeyIdentifier
+	err = testCaseKeyIdentifier.LoadXml(testCaseContext, testCaseDocument.Root())
+	if err != xml.ErrInvalidElementTag {
+		t.Fatal(err)
+	}
+}
+
+func Test_KeyIdentifier_LoadXml(t *testing.T) {
+	// Create test case XML
+	testCaseXml := fmt.Sprintf(
+		`<wsse:KeyIdentifier EncodingType="%s" ValueType="%s" wsu:Id="KI-1" xmlns:wsse="%s" xmlns:wsu="%s">AAECAw==</wsse:KeyIdentifier>`,
+		Base64BinaryEncodingType,
+		ThumbprintSHA1ValueType,
+		WsseNamespace,
+		WsuNamespace,
+	)
+
+	// Prepare the test case
+	testCaseDocument := etree.NewDocument()
+	err := testCaseDocument.ReadFromString(testCaseXml)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCaseContext := xml.NewContext(testCaseDocument)
+	testCaseContext.SetNamespacePrefix("wsu", WsuNamespace)
+
+	// Create test case KeyIdentifier
+	testCaseKeyIdentifier, err := NewKeyIdentifier(testCaseContext)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Load test case KeyIdentifier
+	err = testCaseKeyIdentifier.LoadXml(testCaseContext, testCaseDocument.Root())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Validate test case KeyIdentifier
+	if testCaseKeyIdentifier.GetId() != "KI-1" {
+		t.Fatalf("KeyIdentifier.Id = %s; want KI-1", testCaseKeyIdentifier.GetId())
+	}
+	if testCaseKeyIdentifier.GetValueType() != ThumbprintSHA1ValueType {
+		t.Fatalf("KeyIdentifier.ValueType = %s; want %s", testCaseKeyIdentifier.GetValueType(), ThumbprintSHA1ValueType)
+	}
+	if testCaseKeyIdentifier.GetEncodingType() != Base64BinaryEncodingType {
+		t.Fatalf("KeyIdentifier.EncodingType = %s; want %s", testCaseKeyIdentifier.GetEncodingType(), Base64BinaryEncodingType)
+	}
+	value, err := testCaseKeyIdentifier.GetValueBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(value) != 4 || value[3] != 3 {
+		t.Fatalf("KeyIdentifier.GetValueBytes() = %x; want 00010203", value)
+	}
+}
+
+func Test_KeyIdentifier_GetXml(t *testing.T) {
+	// Create test case XML
+	testCaseXml := fmt.Sprintf(
+		`<wsse:KeyIdentifier EncodingType="%s" ValueType="%s" xmlns:wsse="%s">AAECAw==</wsse:KeyIdentifier>`,
+		Base64BinaryEncodingType,
+		X509SubjectKeyIdentifierValueType,
+		WsseNamespace,
+	)
+
+	// Prepare the test case
+	testCaseDocument := etree.NewDocument()
+	testCaseContext := xml.NewContext(testCaseDocument)
+	testCaseContext.SetNamespacePrefix("wsse", WsseNamespace)
+
+	// Create test case KeyIdentifier
+	testCaseKeyIdentifier, err := NewKeyIdentifier(testCaseContext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCaseKeyIdentifier.SetValueType(X509SubjectKeyIdentifierValueType)
+	testCaseKeyIdentifier.SetEncodingType(Base64BinaryEncodingType)
+	testCaseKeyIdentifier.SetValue("AAECAw==")
+
+	// Get test case KeyIdentifier XML
+	testCaseKeyIdentifierElement, err := testCaseKeyIdentifier.GetXml(testCaseContext)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Add the namespace declarations
+	testCaseKeyIdentifierElement.CreateAttr("xmlns:wsse", WsseNamespace)
+	testCaseKeyIdentifierElement.SortAttrs()
+
+	// Get the test case XML
+	testCaseDocument.SetRoot(testCaseKeyIdentifierElement)
+	resultXml, err := testCaseDocument.WriteToString()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Validate test case XML
+	if resultXml != testCaseXml {
+		t.Fatalf("KeyIdentifier.GetXml() = %s; want %s", resultXml, testCaseXml)
+	}
+}
+
+func Test_KeyIdentifier_GetX509Certificate(t *testing.T) {
+	certificate := newTestCertificate(t, "test", nil, nil).Certificate
+
+	// X509v3 key identifiers embed the certificate
+	testCaseKeyIdentifier, err := NewX509KeyIdentifier(nil, certificate, X509v3ValueType)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := testCaseKeyIdentifier.GetX509Certificate(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Equal(certificate) {
+		t.Fatal("KeyIdentifier.GetX509Certificate() returned a different certificate")
+	}
+
+	// Thumbprints cannot be resolved without a certificate store
+	testCaseKeyIdentifier, err = NewX509KeyIdentifier(nil, certificate, ThumbprintSHA1ValueType)
+	if err != nil {
+		t.Fatal(err)
+	}
+	thumbprint := sha1.Sum(certificate.Raw)
+	if testCaseKeyIdentifier.GetValue() != base64.StdEncoding.EncodeToString(thumbprint[:]) {
+		t.Fatalf("KeyIdentifier.Value = %s; want thumbprint", testCaseKeyIdentifier.GetValue())
+	}
+	_, err = testCaseKeyIdentifier.GetX509Certificate(nil)
+	if err != ErrX509CertificateNotAvailable {
+		t.Fatalf("KeyIdentifier.GetX509Certificate() = %v; want %v", err, ErrX509CertificateNotAvailable)
+	}
+}
+
+func Test_SecurityTokenReference_LoadXml_WithKeyIdentifier(t *testing.T) {
+	certificate := newTestCertificate(t, "test", nil, nil).Certificate
+
+	// Create test case XML
+	testCaseXml := fmt.Sprintf(
+		`<wsse:SecurityTokenReference xmlns:wsse="%s"><wsse:KeyIdentifier EncodingType="%s" ValueType="%s">%s</wsse:KeyIdentifier></wsse:SecurityTokenReference>`,
+		WsseNamespace,
+		Base64BinaryEncodingType,
+		X509v3ValueType,
+		base64.StdEncoding.EncodeToString(certificate.Raw),
+	)
+
+	// Prepare the test case
+	testCaseDocument := etree.NewDocument()
+	err := testCaseDocument.ReadFromString(testCaseXml)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCaseContext := xml.NewContext(testCaseDocument)
+	ConfigureContext(testCaseContext)
+
+	// Load test case SecurityTokenReference
+	testCaseSecurityTokenReference, err := NewSecurityTokenReference(testCaseContext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = testCaseSecurityTokenReference.LoadXml(testCaseContext, testCaseDocument.Root())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Validate the resolved certificate
+	result, err := testCaseSecurityTokenReference.GetX509Certificate(testCaseContext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Equal(certificate) {
+		t.Fatal("SecurityTokenReference.GetX509Certificate() returned a different certificate")
+	}
+}
+
+func Test_KeyIdentifier_GetX509Certificate_CertificateStore(t *testing.T) {
+	certificate := newTestCertificate(t, "test", nil, nil).Certificate
+
+	// Prepare the test case
+	testCaseContext := NewContext(xml.NewContext(etree.NewDocument()))
+	testCaseContext.SetCertificateStore(NewMemoryCertificateStore(certificate))
+
+	for _, valueType := range []string{ThumbprintSHA1ValueType, X509SubjectKeyIdentifierValueType} {
+		testCaseKeyIdentifier, err := NewX509KeyIdentifier(testCaseContext, certificate, valueType)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		result, err := testCaseKeyIdentifier.GetX509Certificate(testCaseContext)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !result.Equal(certificate) {
+			t.Fatalf("KeyIdentifier.GetX509Certificate() returned a different certificate for %s", valueType)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package xmlsecurity
+
+import (
+	"crypto/x509"
+
+	"github.com/beevik/etree"
+	"github.com/deb-ict/go-xml"
+)
+
+type KeyInfo interface {
+	xml.Node
+	X509CertificateProvider
+	X509ChainProvider
+	GetId() string
+	SetId(id string)
+	GetContent() []xml.Node
+	SetContent(content []xml.Node)
+	AddContent(content xml.Node)
+}
+
+type keyInfo struct {
+	Id      string
+	Content []xml.Node
+}
+
+func NewKeyInfo(context xml.Context) (KeyInfo, error) {
+	return &keyInfo{
+		Content: make([]xml.Node, 0),
+	}, nil
+}
+
+func NewKeyInfoNode(context xml.Context) (xml.Node, error) {
+	return NewKeyInfo(context)
+}
+
+func (node *keyInfo) GetId() string {
+	return node.Id
+}
+
+func (node *keyInfo) SetId(id string) {
+	node.Id = id
+}
+
+func (node *keyInfo) GetContent() []xml.Node {
+	return node.Content
+}
+
+func (node *keyInfo) SetContent(content []xml.Node) {
+	node.Content = content
+}
+
+func (node *keyInfo) AddContent(content xml.Node) {
+	node.Content = append(node.Content, content)
+}
+
+func (node *keyInfo) GetX509Certificate(context xml.Context) (*x509.Certificate, error) {
+	for _, item := range node.Content {
+		if provider, ok := item.(X509CertificateProvider); ok {
+			return provider.GetX509Certificate(context)
+		}
+	}
+	return nil, ErrX509CertificateNotAvailable
+}
+
+func (node *keyInfo) GetX509CertificateChain(context xml.Context) ([]*x509.Certificate, error) {
+	for _, item := range node.Content {
+		_, isChainProvider := item.(X509ChainProvider)
+		_, isProvider := item.(X509CertificateProvider)
+		if isChainProvider || isProvider {
+			return getX509CertificateChain(context, item)
+		}
+	}
+	return nil, ErrX509CertificateNotAvailable
+}
+
+func (node *keyInfo) LoadXml(context xml.Context, el *etree.Element) error {
+	err := xml.ValidateElement(el, "KeyInfo", DsigNamespace)
+	if err != nil {
+		return err
+	}
+
+	node.SetId(el.SelectAttrValue("Id", ""))
+
+	content := make([]xml.Node, 0)
+	for _, child := range el.ChildElements() {
+		item, err := loadNode(context, child)
+		if err == xml.ErrNoTypeConstructor {
+			item = &rawNode{}
+			err = item.LoadXml(context, child)
+		}
+		if err != nil {
+			return err
+		}
+		content = append(content, item)
+	}
+	node.SetContent(content)
+
+	return nil
+}
+
+func (node *keyInfo) GetXml(context xml.Context) (*etree.Element, error) {
+	el := etree.NewElement("KeyInfo")
+	el.Space = context.GetNamespacePrefix(DsigNamespace)
+
+	if node.GetId() != "" {
+		el.CreateAttr("Id", node.GetId())
+	}
+
+	for _, item := range node.GetContent() {
+		itemEl, err := item.GetXml(context)
+		if err != nil {
+			return nil, err
+		}
+		el.AddChild(itemEl)
+	}
+
+	return el, nil
+}
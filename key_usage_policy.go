@@ -0,0 +1,144 @@
+package xmlsecurity
+
+import (
+	"crypto/x509"
+	"strings"
+	"sync"
+)
+
+type CertificatePurpose int
+
+const (
+	CertificatePurposeNone CertificatePurpose = iota
+	CertificatePurposeSignature
+	CertificatePurposeEncryption
+	CertificatePurposeHolderOfKey
+)
+
+const (
+	Saml10HolderOfKeyUsage string = "urn:oasis:names:tc:SAML:1.0:cm:holder-of-key"
+	Saml20HolderOfKeyUsage string = "urn:oasis:names:tc:SAML:2.0:cm:holder-of-key"
+)
+
+type KeyUsageRequirement struct {
+	KeyUsage     x509.KeyUsage
+	ExtKeyUsages []x509.ExtKeyUsage
+}
+
+type KeyUsagePolicy interface {
+	MapUsage(usage string, purpose CertificatePurpose)
+	SetRequirement(purpose CertificatePurpose, requirement KeyUsageRequirement)
+	SetDefaultPurpose(purpose CertificatePurpose)
+	GetPurposes(usage string) []CertificatePurpose
+	CheckPurpose(purpose CertificatePurpose, certificate *x509.Certificate) error
+	CheckUsage(usage string, certificate *x509.Certificate) error
+}
+
+type keyUsagePolicy struct {
+	mutex          sync.RWMutex
+	usages         map[string]CertificatePurpose
+	requirements   map[CertificatePurpose]KeyUsageRequirement
+	defaultPurpose CertificatePurpose
+}
+
+func NewKeyUsagePolicy() KeyUsagePolicy {
+	return &keyUsagePolicy{
+		usages: map[string]CertificatePurpose{
+			Saml10HolderOfKeyUsage: CertificatePurposeHolderOfKey,
+			Saml20HolderOfKeyUsage: CertificatePurposeHolderOfKey,
+		},
+		requirements: map[CertificatePurpose]KeyUsageRequirement{
+			CertificatePurposeSignature: {
+				KeyUsage: x509.KeyUsageDigitalSignature | x509.KeyUsageContentCommitment,
+			},
+			CertificatePurposeEncryption: {
+				KeyUsage: x509.KeyUsageKeyEncipherment | x509.KeyUsageDataEncipherment | x509.KeyUsageKeyAgreement,
+			},
+			CertificatePurposeHolderOfKey: {
+				KeyUsage: x509.KeyUsageDigitalSignature,
+			},
+		},
+		defaultPurpose: CertificatePurposeNone,
+	}
+}
+
+func (policy *keyUsagePolicy) MapUsage(usage string, purpose CertificatePurpose) {
+	policy.mutex.Lock()
+	defer policy.mutex.Unlock()
+
+	policy.usages[usage] = purpose
+}
+
+func (policy *keyUsagePolicy) SetRequirement(purpose CertificatePurpose, requirement KeyUsageRequirement) {
+	policy.mutex.Lock()
+	defer policy.mutex.Unlock()
+
+	policy.requirements[purpose] = requirement
+}
+
+func (policy *keyUsagePolicy) SetDefaultPurpose(purpose CertificatePurpose) {
+	policy.mutex.Lock()
+	defer policy.mutex.Unlock()
+
+	policy.defaultPurpose = purpose
+}
+
+func (policy *keyUsagePolicy) GetPurposes(usage string) []CertificatePurpose {
+	policy.mutex.RLock()
+	defer policy.mutex.RUnlock()
+
+	// Usage is a space separated list of URIs; unknown values are ignored
+	purposes := make([]CertificatePurpose, 0)
+	for _, item := range strings.Fields(usage) {
+		purpose, found := policy.usages[item]
+		if found && purpose != CertificatePurposeNone {
+			purposes = append(purposes, purpose)
+		}
+	}
+	if len(purposes) == 0 && policy.defaultPurpose != CertificatePurposeNone {
+		purposes = append(purposes, policy.defaultPurpose)
+	}
+	return purposes
+}
+
+func (policy *keyUsagePolicy) CheckUsage(usage string, certificate *x509.Certificate) error {
+	for _, purpose := range policy.GetPurposes(usage) {
+		err := policy.CheckPurpose(purpose, certificate)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (policy *keyUsagePolicy) CheckPurpose(purpose CertificatePurpose, certificate *x509.Certificate) error {
+	if certificate == nil {
+		return ErrX509CertificateNotAvailable
+	}
+
+	policy.mutex.RLock()
+	requirement, found := policy.requirements[purpose]
+	policy.mutex.RUnlock()
+	if !found {
+		return nil
+	}
+
+	// Certificates without a key usage extension are not restricted
+	if requirement.KeyUsage != 0 && certificate.KeyUsage != 0 && certificate.KeyUsage&requirement.KeyUsage == 0 {
+		return &CertificateValidationError{Reason: CertificateKeyUsageNotAllowed, Certificate: certificate}
+	}
+	if len(requirement.ExtKeyUsages) > 0 && (len(certificate.ExtKeyUsage) > 0 || len(certificate.UnknownExtKeyUsage) > 0) {
+		allowed := false
+		for _, extKeyUsage := range requirement.ExtKeyUsages {
+			if hasExtKeyUsage(certificate, extKeyUsage) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return &CertificateValidationError{Reason: CertificateExtKeyUsageNotAllowed, Certificate: certificate}
+		}
+	}
+
+	return nil
+}
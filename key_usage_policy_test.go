@@ -0,0 +1,113 @@
+package xmlsecurity
+
+import (
+	"crypto/x509"
+	"errors"
+	"testing"
+
+	"github.com/beevik/etree"
+	"github.com/deb-ict/go-xml"
+)
+
+const testEncryptionUsage = "urn:example:usage:encryption"
+
+func Test_KeyUsagePolicy_CheckUsage(t *testing.T) {
+	signing := newTestCertificate(t, "Signing", nil, func(template *x509.Certificate) {
+		template.KeyUsage = x509.KeyUsageDigitalSignature
+		template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}
+	}).Certificate
+	encryption := newTestCertificate(t, "Encryption", nil, func(template *x509.Certificate) {
+		template.KeyUsage = x509.KeyUsageKeyAgreement
+	}).Certificate
+
+	// Create the test case policy
+	policy := NewKeyUsagePolicy()
+	policy.MapUsage(testEncryptionUsage, CertificatePurposeEncryption)
+
+	// Create test case
+	testCase := []struct {
+		name        string
+		usage       string
+		certificate *x509.Certificate
+		rejected    bool
+	}{
+		{name: "no usage", usage: "", certificate: encryption},
+		{name: "unknown usage", usage: "urn:example:unknown", certificate: encryption},
+		{name: "holder of key", usage: Saml20HolderOfKeyUsage, certificate: signing},
+		{name: "holder of key not allowed", usage: Saml20HolderOfKeyUsage, certificate: encryption, rejected: true},
+		{name: "encryption", usage: testEncryptionUsage, certificate: encryption},
+		{name: "encryption not allowed", usage: "urn:example:unknown " + testEncryptionUsage, certificate: signing, rejected: true},
+	}
+
+	for _, tc := range testCase {
+		err := policy.CheckUsage(tc.usage, tc.certificate)
+		if !tc.rejected {
+			if err != nil {
+				t.Fatalf("%s: KeyUsagePolicy.CheckUsage() = %v; want nil", tc.name, err)
+			}
+			continue
+		}
+
+		var validationError *CertificateValidationError
+		if !errors.As(err, &validationError) || validationError.Reason != CertificateKeyUsageNotAllowed {
+			t.Fatalf("%s: KeyUsagePolicy.CheckUsage() = %v; want %s", tc.name, err, CertificateKeyUsageNotAllowed)
+		}
+	}
+
+	// The default purpose applies when no usage is recognized
+	policy.SetDefaultPurpose(CertificatePurposeSignature)
+	err := policy.CheckUsage("", encryption)
+	if err == nil {
+		t.Fatal("KeyUsagePolicy.CheckUsage() with default purpose accepted an encryption certificate")
+	}
+
+	// Extended key usages are enforced when required
+	policy.SetRequirement(CertificatePurposeSignature, KeyUsageRequirement{
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+	})
+	err = policy.CheckPurpose(CertificatePurposeSignature, signing)
+	var validationError *CertificateValidationError
+	if !errors.As(err, &validationError) || validationError.Reason != CertificateExtKeyUsageNotAllowed {
+		t.Fatalf("KeyUsagePolicy.CheckPurpose() = %v; want %s", err, CertificateExtKeyUsageNotAllowed)
+	}
+}
+
+func Test_SecurityTokenReference_GetX509Certificate_KeyUsagePolicy(t *testing.T) {
+	encryption := newTestCertificate(t, "Encryption", nil, func(template *x509.Certificate) {
+		template.KeyUsage = x509.KeyUsageKeyEncipherment
+	}).Certificate
+
+	// Prepare the test case
+	testCaseContext := NewContext(xml.NewContext(etree.NewDocument()))
+	policy := NewKeyUsagePolicy()
+	testCaseContext.SetKeyUsagePolicy(policy)
+
+	// Create the test case SecurityTokenReference
+	testCaseKeyIdentifier, err := NewX509KeyIdentifier(testCaseContext, encryption, X509v3ValueType)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCaseSecurityTokenReference, err := NewSecurityTokenReference(testCaseContext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCaseSecurityTokenReference.SetContent(testCaseKeyIdentifier)
+	testCaseSecurityTokenReference.SetUsage(Saml10HolderOfKeyUsage)
+
+	// Validate the usage is enforced
+	_, err = testCaseSecurityTokenReference.GetX509Certificate(testCaseContext)
+	if err == nil {
+		t.Fatal("SecurityTokenReference.GetX509Certificate() accepted a certificate not allowed for its usage")
+	}
+	_, err = testCaseSecurityTokenReference.GetX509CertificateChain(testCaseContext)
+	if err == nil {
+		t.Fatal("SecurityTokenReference.GetX509CertificateChain() accepted a certificate not allowed for its usage")
+	}
+
+	policy.MapUsage(Saml10HolderOfKeyUsage, CertificatePurposeEncryption)
+	_, err = testCaseSecurityTokenReference.GetX509Certificate(testCaseContext)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
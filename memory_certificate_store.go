@@ -0,0 +1,127 @@
+package xmlsecurity
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"math/big"
+	"sync"
+)
+
+type MemoryCertificateStore interface {
+	CertificateStore
+	AddCertificate(certificate *x509.Certificate)
+	GetCertificates() []*x509.Certificate
+}
+
+type memoryCertificateStore struct {
+	mutex            sync.RWMutex
+	certificates     []*x509.Certificate
+	thumbprintSHA1   map[string]*x509.Certificate
+	thumbprintSHA256 map[string]*x509.Certificate
+	subjectKeyIds    map[string]*x509.Certificate
+	subjects         map[string][]*x509.Certificate
+}
+
+func NewMemoryCertificateStore(certificates ...*x509.Certificate) MemoryCertificateStore {
+	store := &memoryCertificateStore{
+		certificates:     make([]*x509.Certificate, 0),
+		thumbprintSHA1:   make(map[string]*x509.Certificate),
+		thumbprintSHA256: make(map[string]*x509.Certificate),
+		subjectKeyIds:    make(map[string]*x509.Certificate),
+		subjects:         make(map[string][]*x509.Certificate),
+	}
+	for _, certificate := range certificates {
+		store.AddCertificate(certificate)
+	}
+	return store
+}
+
+func (store *memoryCertificateStore) AddCertificate(certificate *x509.Certificate) {
+	if certificate == nil {
+		return
+	}
+
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	thumbprintSHA1 := sha1.Sum(certificate.Raw)
+	key := hex.EncodeToString(thumbprintSHA1[:])
+	if _, found := store.thumbprintSHA1[key]; found {
+		return
+	}
+	thumbprintSHA256 := sha256.Sum256(certificate.Raw)
+
+	store.certificates = append(store.certificates, certificate)
+	store.thumbprintSHA1[key] = certificate
+	store.thumbprintSHA256[hex.EncodeToString(thumbprintSHA256[:])] = certificate
+	if len(certificate.SubjectKeyId) > 0 {
+		store.subjectKeyIds[hex.EncodeToString(certificate.SubjectKeyId)] = certificate
+	}
+	subject := normalizeDistinguishedName(certificate.Subject.String())
+	store.subjects[subject] = append(store.subjects[subject], certificate)
+}
+
+func (store *memoryCertificateStore) GetCertificates() []*x509.Certificate {
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+
+	result := make([]*x509.Certificate, len(store.certificates))
+	copy(result, store.certificates)
+	return result
+}
+
+func (store *memoryCertificateStore) FindByThumbprintSHA1(thumbprint []byte) (*x509.Certificate, error) {
+	return store.find(store.thumbprintSHA1, thumbprint)
+}
+
+func (store *memoryCertificateStore) FindByThumbprintSHA256(thumbprint []byte) (*x509.Certificate, error) {
+	return store.find(store.thumbprintSHA256, thumbprint)
+}
+
+func (store *memoryCertificateStore) FindBySubjectKeyId(subjectKeyId []byte) (*x509.Certificate, error) {
+	return store.find(store.subjectKeyIds, subjectKeyId)
+}
+
+func (store *memoryCertificateStore) FindByIssuerSerial(issuer string, serialNumber *big.Int) (*x509.Certificate, error) {
+	if serialNumber == nil {
+		return nil, ErrCertificateNotFound
+	}
+
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+
+	issuer = normalizeDistinguishedName(issuer)
+	for _, certificate := range store.certificates {
+		if certificate.SerialNumber.Cmp(serialNumber) == 0 && normalizeDistinguishedName(certificate.Issuer.String()) == issuer {
+			return certificate, nil
+		}
+	}
+	return nil, ErrCertificateNotFound
+}
+
+func (store *memoryCertificateStore) FindBySubject(subject string) ([]*x509.Certificate, error) {
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+
+	certificates, found := store.subjects[normalizeDistinguishedName(subject)]
+	if !found {
+		return nil, ErrCertificateNotFound
+	}
+
+	result := make([]*x509.Certificate, len(certificates))
+	copy(result, certificates)
+	return result, nil
+}
+
+func (store *memoryCertificateStore) find(index map[string]*x509.Certificate, key []byte) (*x509.Certificate, error) {
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+
+	certificate, found := index[hex.EncodeToString(key)]
+	if !found || len(key) == 0 {
+		return nil, ErrCertificateNotFound
+	}
+	return certificate, nil
+}
@@ -0,0 +1,91 @@
+package xmlsecurity
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"math/big"
+	"testing"
+)
+
+func Test_MemoryCertificateStore_Find(t *testing.T) {
+	root := newTestCertificate(t, "Test Root", nil, nil)
+	leaf := newTestCertificate(t, "Test Leaf", root, nil)
+
+	// Create test case store
+	store := NewMemoryCertificateStore(root.Certificate, leaf.Certificate, leaf.Certificate)
+	if len(store.GetCertificates()) != 2 {
+		t.Fatalf("len(MemoryCertificateStore.Certificates) = %d; want 2", len(store.GetCertificates()))
+	}
+
+	thumbprintSHA1 := sha1.Sum(leaf.Certificate.Raw)
+	result, err := store.FindByThumbprintSHA1(thumbprintSHA1[:])
+	if err != nil || !result.Equal(leaf.Certificate) {
+		t.Fatalf("MemoryCertificateStore.FindByThumbprintSHA1() = %v", err)
+	}
+
+	thumbprintSHA256 := sha256.Sum256(leaf.Certificate.Raw)
+	result, err = store.FindByThumbprintSHA256(thumbprintSHA256[:])
+	if err != nil || !result.Equal(leaf.Certificate) {
+		t.Fatalf("MemoryCertificateStore.FindByThumbprintSHA256() = %v", err)
+	}
+
+	result, err = store.FindBySubjectKeyId(root.Certificate.SubjectKeyId)
+	if err != nil || !result.Equal(root.Certificate) {
+		t.Fatalf("MemoryCertificateStore.FindBySubjectKeyId() = %v", err)
+	}
+
+	result, err = store.FindByIssuerSerial("o=deb-ict, cn=Test Root", leaf.Certificate.SerialNumber)
+	if err == nil {
+		t.Fatal("MemoryCertificateStore.FindByIssuerSerial() matched an issuer in the wrong order")
+	}
+	result, err = store.FindByIssuerSerial("cn=test root, o=deb-ict", leaf.Certificate.SerialNumber)
+	if err != nil || !result.Equal(leaf.Certificate) {
+		t.Fatalf("MemoryCertificateStore.FindByIssuerSerial() = %v", err)
+	}
+
+	results, err := store.FindBySubject(leaf.Certificate.Subject.String())
+	if err != nil || len(results) != 1 || !results[0].Equal(leaf.Certificate) {
+		t.Fatalf("MemoryCertificateStore.FindBySubject() = %v", err)
+	}
+}
+
+func Test_MemoryCertificateStore_NotFound(t *testing.T) {
+	store := NewMemoryCertificateStore()
+
+	_, err := store.FindByThumbprintSHA1([]byte{0x01})
+	if err != ErrCertificateNotFound {
+		t.Fatalf("MemoryCertificateStore.FindByThumbprintSHA1() = %v; want %v", err, ErrCertificateNotFound)
+	}
+	_, err = store.FindBySubjectKeyId(nil)
+	if err != ErrCertificateNotFound {
+		t.Fatalf("MemoryCertificateStore.FindBySubjectKeyId() = %v; want %v", err, ErrCertificateNotFound)
+	}
+	_, err = store.FindByIssuerSerial("CN=Test", big.NewInt(1))
+	if err != ErrCertificateNotFound {
+		t.Fatalf("MemoryCertificateStore.FindByIssuerSerial() = %v; want %v", err, ErrCertificateNotFound)
+	}
+	_, err = store.FindBySubject("CN=Test")
+	if err != ErrCertificateNotFound {
+		t.Fatalf("MemoryCertificateStore.FindBySubject() = %v; want %v", err, ErrCertificateNotFound)
+	}
+}
+
+func Test_NormalizeDistinguishedName(t *testing.T) {
+	// Create test case
+	testCase := []struct {
+		name     string
+		expected string
+	}{
+		{name: "CN=Test, O=deb-ict", expected: "CN=test,O=deb-ict"},
+		{name: "cn = Test;o=DEB-ICT", expected: "CN=test,O=deb-ict"},
+		{name: `CN=Test\, Inc.,C=BE`, expected: `CN=test\, inc.,C=be`},
+		{name: "E=info@example.com", expected: "1.2.840.113549.1.9.1=info@example.com"},
+	}
+
+	for _, tc := range testCase {
+		result := normalizeDistinguishedName(tc.name)
+		if result != tc.expected {
+			t.Fatalf("normalizeDistinguishedName(%s) = %s; want %s", tc.name, result, tc.expected)
+		}
+	}
+}
@@ -0,0 +1,289 @@
+package xmlsecurity
+
+import (
+	"crypto"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+
+	"github.com/beevik/etree"
+	"github.com/deb-ict/go-xml"
+)
+
+var (
+	ErrNothingToSign = errors.New("no elements to sign")
+)
+
+type MessageSignerOptions struct {
+	Signer                    crypto.Signer
+	Certificate               *x509.Certificate
+	SignatureAlgorithm        string
+	DigestAlgorithm           string
+	CanonicalizationAlgorithm string
+	InclusiveNamespaces       []string
+	SignBinarySecurityToken   bool
+}
+
+type MessageSigner interface {
+	Sign(context xml.Context, security *etree.Element, elements ...*etree.Element) (Signature, error)
+	SignIds(context xml.Context, security *etree.Element, ids ...string) (Signature, error)
+}
+
+type messageSigner struct {
+	options MessageSignerOptions
+}
+
+func NewMessageSigner(options MessageSignerOptions) (MessageSigner, error) {
+	if options.Signer == nil {
+		return nil, ErrInvalidPrivateKey
+	}
+	if options.Certificate == nil {
+		return nil, ErrX509CertificateNotAvailable
+	}
+	publicKey, ok := options.Signer.Public().(publicKeyComparer)
+	if !ok || !publicKey.Equal(options.Certificate.PublicKey) {
+		return nil, ErrPrivateKeyMismatch
+	}
+
+	if options.SignatureAlgorithm == "" {
+		algorithm, err := getDefaultSignatureAlgorithm(options.Certificate.PublicKey)
+		if err != nil {
+			return nil, err
+		}
+		options.SignatureAlgorithm = algorithm
+	}
+	err := checkSignatureAlgorithmKey(options.SignatureAlgorithm, options.Certificate.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	if options.DigestAlgorithm == "" {
+		options.DigestAlgorithm = SHA256DigestAlgorithm
+	}
+	_, err = getDigestHash(options.DigestAlgorithm)
+	if err != nil {
+		return nil, err
+	}
+	if options.CanonicalizationAlgorithm == "" {
+		options.CanonicalizationAlgorithm = ExcC14NAlgorithm
+	}
+	_, err = NewCanonicalizer(options.CanonicalizationAlgorithm, options.InclusiveNamespaces)
+	if err != nil {
+		return nil, err
+	}
+
+	return &messageSigner{
+		options: options,
+	}, nil
+}
+
+func (signer *messageSigner) SignIds(context xml.Context, security *etree.Element, ids ...string) (Signature, error) {
+	defer retainIdIndex(context)()
+
+	index, err := GetIdIndex(context)
+	if err != nil {
+		return nil, err
+	}
+
+	elements := make([]*etree.Element, 0, len(ids))
+	for _, id := range ids {
+		el, err := index.GetElement(id)
+		if err != nil {
+			return nil, err
+		}
+		elements = append(elements, el)
+	}
+	return signer.Sign(context, security, elements...)
+}
+
+func (signer *messageSigner) Sign(context xml.Context, security *etree.Element, elements ...*etree.Element) (Signature, error) {
+	if security == nil {
+		return nil, xml.ErrElementIsNil
+	}
+	defer retainIdIndex(context)()
+
+	if len(elements) == 0 && !signer.options.SignBinarySecurityToken {
+		return nil, ErrNothingToSign
+	}
+	for _, el := range elements {
+		if el == nil {
+			return nil, xml.ErrElementIsNil
+		}
+	}
+
+	// The token is added first so the signature can reference it
+	token, err := NewX509BinarySecurityToken(context, signer.options.Certificate)
+	if err != nil {
+		return nil, err
+	}
+	tokenEl, err := token.GetXml(context)
+	if err != nil {
+		return nil, err
+	}
+	security.AddChild(tokenEl)
+	declareContextNamespaces(context, tokenEl)
+	resetIdIndex(context)
+	if signer.options.SignBinarySecurityToken {
+		elements = append(elements, tokenEl)
+	}
+
+	// Elements are referenced by id, a wsu:Id is generated where missing
+	ids := make([]string, len(elements))
+	generator := GetIdGenerator(context)
+	for i, el := range elements {
+		ids[i] = GetIdResolver(context).GetElementId(context, el)
+		if ids[i] != "" {
+			continue
+		}
+		ids[i], err = generator.GenerateId(context, el.Tag)
+		if err != nil {
+			return nil, err
+		}
+		SetWsuId(context, el, ids[i])
+	}
+	resetIdIndex(context)
+
+	signedInfo, err := signer.newSignedInfo(context, security, elements, ids)
+	if err != nil {
+		return nil, err
+	}
+	keyInfo, err := signer.newKeyInfo(context, token)
+	if err != nil {
+		return nil, err
+	}
+	signatureValue, err := NewSignatureValue(context)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := NewSignature(context)
+	if err != nil {
+		return nil, err
+	}
+	signature.SetSignedInfo(signedInfo)
+	signature.SetSignatureValue(signatureValue)
+	signature.SetKeyInfo(keyInfo)
+
+	signatureEl, err := signature.GetXml(context)
+	if err != nil {
+		return nil, err
+	}
+	security.AddChild(signatureEl)
+	declareContextNamespaces(context, signatureEl)
+
+	// SignedInfo is canonicalized in place so inherited namespaces are taken into account
+	canonicalizer, err := signedInfo.GetCanonicalizationMethod().GetCanonicalizer()
+	if err != nil {
+		return nil, err
+	}
+	data, err := canonicalizer.Canonicalize(signatureEl.ChildElements()[0])
+	if err != nil {
+		return nil, err
+	}
+	value, err := createSignatureValue(signer.options.Signer, signer.options.SignatureAlgorithm, data)
+	if err != nil {
+		return nil, err
+	}
+	signatureValue.SetValue(value)
+	signatureEl.ChildElements()[1].SetText(base64.StdEncoding.EncodeToString(value))
+
+	return signature, nil
+}
+
+func (signer *messageSigner) newSignedInfo(context xml.Context, security *etree.Element, elements []*etree.Element, ids []string) (SignedInfo, error) {
+	signedInfo, err := NewSignedInfo(context)
+	if err != nil {
+		return nil, err
+	}
+
+	canonicalizationMethod, err := NewCanonicalizationMethod(context)
+	if err != nil {
+		return nil, err
+	}
+	canonicalizationMethod.SetAlgorithm(signer.options.CanonicalizationAlgorithm)
+	canonicalizationMethod.SetInclusiveNamespaces(signer.getInclusiveNamespaces())
+	signedInfo.SetCanonicalizationMethod(canonicalizationMethod)
+
+	signatureMethod, err := NewSignatureMethod(context)
+	if err != nil {
+		return nil, err
+	}
+	signatureMethod.SetAlgorithm(signer.options.SignatureAlgorithm)
+	signedInfo.SetSignatureMethod(signatureMethod)
+
+	for i, el := range elements {
+		reference, err := NewSignatureReference(context)
+		if err != nil {
+			return nil, err
+		}
+		reference.SetUri("#" + ids[i])
+
+		// Elements enclosing the security header are signed without the signature itself
+		if containsElement(el, security) {
+			envelopedTransform, err := NewTransform(context)
+			if err != nil {
+				return nil, err
+			}
+			envelopedTransform.SetAlgorithm(EnvelopedSignatureAlgorithm)
+			reference.AddTransform(envelopedTransform)
+		}
+		transform, err := NewTransform(context)
+		if err != nil {
+			return nil, err
+		}
+		transform.SetAlgorithm(signer.options.CanonicalizationAlgorithm)
+		transform.SetInclusiveNamespaces(signer.getInclusiveNamespaces())
+		reference.AddTransform(transform)
+
+		digestMethod, err := NewDigestMethod(context)
+		if err != nil {
+			return nil, err
+		}
+		digestMethod.SetAlgorithm(signer.options.DigestAlgorithm)
+		reference.SetDigestMethod(digestMethod)
+
+		digestValue, err := digestReference(reference, el, nil)
+		if err != nil {
+			return nil, err
+		}
+		reference.SetDigestValue(digestValue)
+		signedInfo.AddReference(reference)
+	}
+
+	return signedInfo, nil
+}
+
+func (signer *messageSigner) newKeyInfo(context xml.Context, token BinarySecurityToken) (KeyInfo, error) {
+	reference, err := NewReference(context)
+	if err != nil {
+		return nil, err
+	}
+	reference.SetUri("#" + token.GetId())
+	reference.SetValueType(token.GetValueType())
+
+	securityTokenReference, err := NewSecurityTokenReference(context)
+	if err != nil {
+		return nil, err
+	}
+	securityTokenReference.SetContent(reference)
+
+	keyInfo, err := NewKeyInfo(context)
+	if err != nil {
+		return nil, err
+	}
+	keyInfo.AddContent(securityTokenReference)
+	return keyInfo, nil
+}
+
+func (signer *messageSigner) getInclusiveNamespaces() []string {
+	// Only exclusive canonicalization carries an InclusiveNamespaces prefix list
+	switch signer.options.CanonicalizationAlgorithm {
+	case ExcC14NAlgorithm, ExcC14NWithCommentsAlgorithm:
+		return signer.options.InclusiveNamespaces
+	}
+	return nil
+}
+
+func resetIdIndex(context xml.Context) {
+	if securityContext, ok := context.(Context); ok {
+		securityContext.ResetIdIndex()
+	}
+}
@@ -0,0 +1,308 @@
+package xmlsecurity
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/beevik/etree"
+	"github.com/deb-ict/go-xml"
+)
+
+func newTestSoapEnvelope(t *testing.T) (Context, *etree.Document) {
+	t.Helper()
+
+	// Create test case XML
+	testCaseXml := fmt.Sprintf(
+		`<soap:Envelope xmlns:soap="%s">`+
+			`<soap:Header>`+
+			`<o:Security xmlns:o="%s" xmlns:u="%s">`+
+			`<u:Timestamp u:Id="TS-1"><u:Created>2024-01-01T00:00:00.000Z</u:Created></u:Timestamp>`+
+			`</o:Security>`+
+			`</soap:Header>`+
+			`<soap:Body><Payload xmlns="urn:payload">data</Payload></soap:Body>`+
+			`</soap:Envelope>`,
+		Soap11Namespace,
+		WsseNamespace,
+		WsuNamespace,
+	)
+
+	// Prepare the test case
+	testCaseDocument := etree.NewDocument()
+	err := testCaseDocument.ReadFromString(testCaseXml)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCaseContext := NewContext(xml.NewContext(testCaseDocument))
+	ConfigureContext(testCaseContext)
+	return testCaseContext, testCaseDocument
+}
+
+func checkTestSignature(t *testing.T, context xml.Context, signatureEl *etree.Element, certificate *x509.Certificate) {
+	t.Helper()
+
+	// Load the signature from the document
+	signature, err := NewSignature(context)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = signature.LoadXml(context, signatureEl)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Recompute the reference digests
+	index, err := NewIdIndex(context, GetIdResolver(context))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, reference := range signature.GetSignedInfo().GetReferences() {
+		el, err := index.ResolveUri(reference.GetUri())
+		if err != nil {
+			t.Fatal(err)
+		}
+		digestValue, err := digestReference(reference, el, signatureEl)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(digestValue, reference.GetDigestValue()) {
+			t.Fatalf("SignatureReference(%s).DigestValue does not match the element", reference.GetUri())
+		}
+	}
+
+	// Verify the signature value
+	canonicalizer, err := signature.GetSignedInfo().GetCanonicalizationMethod().GetCanonicalizer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := canonicalizer.Canonicalize(signatureEl.SelectElement("SignedInfo"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = verifySignatureValue(certificate.PublicKey, signature.GetSignedInfo().GetSignatureMethod().GetAlgorithm(), data, signature.GetSignatureValue().GetValue())
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_MessageSigner_Sign(t *testing.T) {
+	testCaseCertificate := newTestCertificate(t, "Test Signer", nil, nil)
+	testCaseContext, testCaseDocument := newTestSoapEnvelope(t)
+	security := testCaseDocument.FindElement("//Security")
+	body := testCaseDocument.FindElement("//Body")
+
+	// Create test case MessageSigner
+	testCaseMessageSigner, err := NewMessageSigner(MessageSignerOptions{
+		Signer:                  testCaseCertificate.PrivateKey,
+		Certificate:             testCaseCertificate.Certificate,
+		InclusiveNamespaces:     []string{"soap"},
+		SignBinarySecurityToken: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Sign the test case Body and Timestamp
+	testCaseSignature, err := testCaseMessageSigner.Sign(testCaseContext, security, body, testCaseDocument.FindElement("//Timestamp"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Validate the generated Body id
+	bodyId := GetWsuId(testCaseContext, body)
+	if !strings.HasPrefix(bodyId, "Body-") {
+		t.Fatalf("Body wsu:Id = %s; want Body- prefix", bodyId)
+	}
+	if body.SelectAttr("xmlns:wsu") == nil || body.SelectAttr("wsu:Id") == nil {
+		t.Fatal("Body wsu:Id was added without a namespace declaration")
+	}
+
+	// Validate the Security header layout
+	children := security.ChildElements()
+	if len(children) != 3 || children[0].Tag != "Timestamp" || children[1].Tag != "BinarySecurityToken" || children[2].Tag != "Signature" {
+		t.Fatal("Security header does not contain Timestamp, BinarySecurityToken and Signature")
+	}
+	tokenId := GetWsuId(testCaseContext, children[1])
+	references := testCaseSignature.GetSignedInfo().GetReferences()
+	if len(references) != 3 || references[0].GetUri() != "#"+bodyId || references[1].GetUri() != "#TS-1" || references[2].GetUri() != "#"+tokenId {
+		t.Fatal("SignedInfo.References do not point to the Body, Timestamp and BinarySecurityToken")
+	}
+	securityTokenReference, ok := testCaseSignature.GetKeyInfo().GetContent()[0].(SecurityTokenReference)
+	if !ok {
+		t.Fatal("KeyInfo does not contain a SecurityTokenReference")
+	}
+	if securityTokenReference.GetContent().(Reference).GetUri() != "#"+tokenId {
+		t.Fatal("KeyInfo SecurityTokenReference does not point to the BinarySecurityToken")
+	}
+	if children[2].Space != "ds" || children[2].SelectAttrValue("xmlns:ds", "") != DsigNamespace {
+		t.Fatal("Signature does not declare the dsig namespace")
+	}
+
+	// Validate the signature after a round trip
+	resultXml, err := testCaseDocument.WriteToString()
+	if err != nil {
+		t.Fatal(err)
+	}
+	resultDocument := etree.NewDocument()
+	err = resultDocument.ReadFromString(resultXml)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resultContext := xml.NewContext(resultDocument)
+	ConfigureContext(resultContext)
+	checkTestSignature(t, resultContext, resultDocument.FindElement("//Signature"), testCaseCertificate.Certificate)
+
+	// Resolve the signing certificate through KeyInfo
+	resolved, err := securityTokenReference.GetX509Certificate(resultContext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !resolved.Equal(testCaseCertificate.Certificate) {
+		t.Fatal("KeyInfo did not resolve to the signing certificate")
+	}
+}
+
+func Test_MessageSigner_SignIds_Rsa(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "Test RSA Signer"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, privateKey.Public(), privateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certificate, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Create test cases
+	testCases := []string{
+		ExcC14NAlgorithm,
+		C14N10Algorithm,
+		C14N11WithCommentsAlgorithm,
+	}
+
+	for _, tc := range testCases {
+		testCaseContext, testCaseDocument := newTestSoapEnvelope(t)
+
+		// Create test case MessageSigner
+		testCaseMessageSigner, err := NewMessageSigner(MessageSignerOptions{
+			Signer:                    privateKey,
+			Certificate:               certificate,
+			DigestAlgorithm:           SHA512DigestAlgorithm,
+			CanonicalizationAlgorithm: tc,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Sign the test case Timestamp by id
+		testCaseSignature, err := testCaseMessageSigner.SignIds(testCaseContext, testCaseDocument.FindElement("//Security"), "TS-1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if testCaseSignature.GetSignedInfo().GetSignatureMethod().GetAlgorithm() != RSASHA256SignatureAlgorithm {
+			t.Fatalf("SignatureMethod.Algorithm = %s; want %s", testCaseSignature.GetSignedInfo().GetSignatureMethod().GetAlgorithm(), RSASHA256SignatureAlgorithm)
+		}
+		checkTestSignature(t, testCaseContext, testCaseDocument.FindElement("//Signature"), certificate)
+	}
+}
+
+func Test_MessageSigner_Sign_Enveloped(t *testing.T) {
+	testCaseCertificate := newTestCertificate(t, "Test Signer", nil, nil)
+	testCaseContext, testCaseDocument := newTestSoapEnvelope(t)
+
+	// Create test case MessageSigner
+	testCaseMessageSigner, err := NewMessageSigner(MessageSignerOptions{
+		Signer:      testCaseCertificate.PrivateKey,
+		Certificate: testCaseCertificate.Certificate,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Sign the test case Envelope
+	testCaseSignature, err := testCaseMessageSigner.Sign(testCaseContext, testCaseDocument.FindElement("//Security"), testCaseDocument.Root())
+	if err != nil {
+		t.Fatal(err)
+	}
+	transforms := testCaseSignature.GetSignedInfo().GetReferences()[0].GetTransforms()
+	if len(transforms) != 2 || transforms[0].GetAlgorithm() != EnvelopedSignatureAlgorithm {
+		t.Fatal("SignatureReference does not start with the enveloped signature transform")
+	}
+	checkTestSignature(t, testCaseContext, testCaseDocument.FindElement("//Signature"), testCaseCertificate.Certificate)
+}
+
+func Test_NewMessageSigner_Errors(t *testing.T) {
+	testCaseCertificate := newTestCertificate(t, "Test Signer", nil, nil)
+	otherCertificate := newTestCertificate(t, "Other Signer", nil, nil)
+
+	// Create test cases
+	testCases := []struct {
+		options MessageSignerOptions
+		err     error
+	}{
+		{
+			options: MessageSignerOptions{Certificate: testCaseCertificate.Certificate},
+			err:     ErrInvalidPrivateKey,
+		},
+		{
+			options: MessageSignerOptions{Signer: testCaseCertificate.PrivateKey},
+			err:     ErrX509CertificateNotAvailable,
+		},
+		{
+			options: MessageSignerOptions{Signer: testCaseCertificate.PrivateKey, Certificate: otherCertificate.Certificate},
+			err:     ErrPrivateKeyMismatch,
+		},
+		{
+			options: MessageSignerOptions{Signer: testCaseCertificate.PrivateKey, Certificate: testCaseCertificate.Certificate, SignatureAlgorithm: RSASHA256SignatureAlgorithm},
+			err:     ErrSignatureAlgorithmKeyMismatch,
+		},
+		{
+			options: MessageSignerOptions{Signer: testCaseCertificate.PrivateKey, Certificate: testCaseCertificate.Certificate, SignatureAlgorithm: "urn:unknown"},
+			err:     ErrUnsupportedSignatureAlgorithm,
+		},
+		{
+			options: MessageSignerOptions{Signer: testCaseCertificate.PrivateKey, Certificate: testCaseCertificate.Certificate, DigestAlgorithm: "urn:unknown"},
+			err:     ErrUnsupportedDigestAlgorithm,
+		},
+		{
+			options: MessageSignerOptions{Signer: testCaseCertificate.PrivateKey, Certificate: testCaseCertificate.Certificate, CanonicalizationAlgorithm: "urn:unknown"},
+			err:     ErrUnsupportedCanonicalizationAlgorithm,
+		},
+	}
+
+	for _, tc := range testCases {
+		// Create test case MessageSigner
+		_, err := NewMessageSigner(tc.options)
+		if err != tc.err {
+			t.Fatalf("NewMessageSigner() error = %v; want %v", err, tc.err)
+		}
+	}
+
+	// Sign without elements
+	testCaseMessageSigner, err := NewMessageSigner(MessageSignerOptions{Signer: testCaseCertificate.PrivateKey, Certificate: testCaseCertificate.Certificate})
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCaseContext, testCaseDocument := newTestSoapEnvelope(t)
+	_, err = testCaseMessageSigner.Sign(testCaseContext, testCaseDocument.FindElement("//Security"))
+	if err != ErrNothingToSign {
+		t.Fatalf("MessageSigner.Sign() error = %v; want %v", err, ErrNothingToSign)
+	}
+}
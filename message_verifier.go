@@ -0,0 +1,178 @@
+package xmlsecurity
+
+import (
+	"bytes"
+	"crypto/x509"
+	"errors"
+	"fmt"
+
+	"github.com/beevik/etree"
+	"github.com/deb-ict/go-xml"
+)
+
+var (
+	ErrSignatureNotFound            = errors.New("signature not found")
+	ErrDigestValueMismatch          = errors.New("digest value mismatch")
+	ErrCertificateValidatorRequired = errors.New("certificate validator required")
+)
+
+type MessageVerifierOptions struct {
+	CertificateValidator              CertificateValidator
+	InsecureSkipCertificateValidation bool
+}
+
+type SignedElement struct {
+	Uri     string
+	Id      string
+	Element *etree.Element
+}
+
+type MessageVerification struct {
+	Signature        Signature
+	Certificate      *x509.Certificate
+	CertificateChain []*x509.Certificate
+	SignedElements   []SignedElement
+}
+
+type MessageVerifier interface {
+	Verify(context xml.Context, security *etree.Element) (*MessageVerification, error)
+}
+
+type messageVerifier struct {
+	options MessageVerifierOptions
+}
+
+func NewMessageVerifier(options MessageVerifierOptions) (MessageVerifier, error) {
+	if options.CertificateValidator == nil && !options.InsecureSkipCertificateValidation {
+		return nil, ErrCertificateValidatorRequired
+	}
+
+	return &messageVerifier{
+		options: options,
+	}, nil
+}
+
+func (verification *MessageVerification) IsSigned(el *etree.Element) bool {
+	for _, signed := range verification.SignedElements {
+		if signed.Element == el {
+			return true
+		}
+	}
+	return false
+}
+
+func (verification *MessageVerification) IsSignedId(id string) bool {
+	for _, signed := range verification.SignedElements {
+		if signed.Id == id {
+			return true
+		}
+	}
+	return false
+}
+
+func (verifier *messageVerifier) Verify(context xml.Context, security *etree.Element) (*MessageVerification, error) {
+	if security == nil {
+		return nil, xml.ErrElementIsNil
+	}
+	defer retainIdIndex(context)()
+
+	var signatureEl *etree.Element
+	for _, child := range security.ChildElements() {
+		if isElement(context, child, "Signature", DsigNamespace) {
+			signatureEl = child
+			break
+		}
+	}
+	if signatureEl == nil {
+		return nil, ErrSignatureNotFound
+	}
+
+	// The signature value and the digests are checked against one SignedInfo element
+	var signedInfoEl *etree.Element
+	for _, child := range signatureEl.ChildElements() {
+		if !isElement(context, child, "SignedInfo", DsigNamespace) {
+			continue
+		}
+		if signedInfoEl != nil {
+			return nil, ErrUnexpectedChildElement
+		}
+		signedInfoEl = child
+	}
+	signature, err := NewSignature(context)
+	if err != nil {
+		return nil, err
+	}
+	err = signature.LoadXml(context, signatureEl)
+	if err != nil {
+		return nil, err
+	}
+	signedInfo, err := NewSignedInfo(context)
+	if err != nil {
+		return nil, err
+	}
+	err = signedInfo.LoadXml(context, signedInfoEl)
+	if err != nil {
+		return nil, err
+	}
+	signature.SetSignedInfo(signedInfo)
+	if signature.GetKeyInfo() == nil {
+		return nil, ErrX509CertificateNotAvailable
+	}
+
+	chain, err := verifier.getCertificateChain(context, signature.GetKeyInfo())
+	if err != nil {
+		return nil, err
+	}
+
+	// SignedInfo is canonicalized as it appears in the document
+	canonicalizer, err := signedInfo.GetCanonicalizationMethod().GetCanonicalizer()
+	if err != nil {
+		return nil, err
+	}
+	data, err := canonicalizer.Canonicalize(signedInfoEl)
+	if err != nil {
+		return nil, err
+	}
+	err = verifySignatureValue(chain[0].PublicKey, signedInfo.GetSignatureMethod().GetAlgorithm(), data, signature.GetSignatureValue().GetValue())
+	if err != nil {
+		return nil, err
+	}
+
+	index, err := GetIdIndex(context)
+	if err != nil {
+		return nil, err
+	}
+	signedElements := make([]SignedElement, 0, len(signedInfo.GetReferences()))
+	for _, reference := range signedInfo.GetReferences() {
+		el, err := index.ResolveUri(reference.GetUri())
+		if err != nil {
+			return nil, err
+		}
+		digestValue, err := digestReference(reference, el, signatureEl)
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(digestValue, reference.GetDigestValue()) {
+			return nil, fmt.Errorf("%w: %s", ErrDigestValueMismatch, reference.GetUri())
+		}
+		signedElements = append(signedElements, SignedElement{
+			Uri:     reference.GetUri(),
+			Id:      reference.GetUri()[1:],
+			Element: el,
+		})
+	}
+
+	return &MessageVerification{
+		Signature:        signature,
+		Certificate:      chain[0],
+		CertificateChain: chain,
+		SignedElements:   signedElements,
+	}, nil
+}
+
+func (verifier *messageVerifier) getCertificateChain(context xml.Context, keyInfo KeyInfo) ([]*x509.Certificate, error) {
+	if verifier.options.CertificateValidator == nil {
+		return keyInfo.GetX509CertificateChain(context)
+	}
+	return verifier.options.CertificateValidator.ValidateProvider(context, keyInfo)
+}
@@ -0,0 +1,274 @@
+package xmlsecurity
+
+import (
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	"github.com/beevik/etree"
+	"github.com/deb-ict/go-xml"
+)
+
+func newTestSignedSoapEnvelope(t *testing.T, certificate *testCertificate) *etree.Document {
+	t.Helper()
+
+	// Sign the test case Body and Timestamp
+	testCaseContext, testCaseDocument := newTestSoapEnvelope(t)
+	signer, err := NewMessageSigner(MessageSignerOptions{
+		Signer:                  certificate.PrivateKey,
+		Certificate:             certificate.Certificate,
+		SignBinarySecurityToken: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = signer.Sign(testCaseContext, testCaseDocument.FindElement("//Security"), testCaseDocument.FindElement("//Body"), testCaseDocument.FindElement("//Timestamp"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Reload the signed document
+	signedXml, err := testCaseDocument.WriteToString()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signedDocument := etree.NewDocument()
+	err = signedDocument.ReadFromString(signedXml)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signedDocument
+}
+
+func newTestVerifierContext(doc *etree.Document) Context {
+	context := NewContext(xml.NewContext(doc))
+	ConfigureContext(context)
+	return context
+}
+
+func Test_MessageVerifier_Verify(t *testing.T) {
+	testCaseCertificate := newTestCertificate(t, "Test Signer", nil, nil)
+	testCaseDocument := newTestSignedSoapEnvelope(t, testCaseCertificate)
+	testCaseContext := newTestVerifierContext(testCaseDocument)
+
+	// Verify the test case document
+	verifier, err := NewMessageVerifier(MessageVerifierOptions{
+		CertificateValidator: NewCertificateValidator(CertificateValidatorOptions{
+			Roots: []*x509.Certificate{testCaseCertificate.Certificate},
+		}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	verification, err := verifier.Verify(testCaseContext, testCaseDocument.FindElement("//Security"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Validate the verification result
+	if !verification.Certificate.Equal(testCaseCertificate.Certificate) {
+		t.Fatal("MessageVerification.Certificate is not the signing certificate")
+	}
+	body := testCaseDocument.FindElement("//Body")
+	timestamp := testCaseDocument.FindElement("//Timestamp")
+	token := testCaseDocument.FindElement("//BinarySecurityToken")
+	if len(verification.SignedElements) != 3 {
+		t.Fatalf("MessageVerification.SignedElements = %d; want %d", len(verification.SignedElements), 3)
+	}
+	if !verification.IsSigned(body) || !verification.IsSigned(timestamp) || !verification.IsSigned(token) {
+		t.Fatal("MessageVerification does not list the signed elements")
+	}
+	if verification.IsSigned(body.ChildElements()[0]) || verification.IsSigned(testCaseDocument.FindElement("//Header")) {
+		t.Fatal("MessageVerification lists elements that are not referenced")
+	}
+	if !verification.IsSignedId("TS-1") || verification.SignedElements[1].Uri != "#TS-1" {
+		t.Fatal("MessageVerification does not list the Timestamp id")
+	}
+}
+
+func Test_MessageVerifier_Verify_CertificateValidator(t *testing.T) {
+	root := newTestCertificate(t, "Test Root", nil, nil)
+	leaf := newTestCertificate(t, "Test Leaf", root, nil)
+	other := newTestCertificate(t, "Other Root", nil, nil)
+	testCaseDocument := newTestSignedSoapEnvelope(t, leaf)
+
+	// Verify with a trusted root
+	verifier, err := NewMessageVerifier(MessageVerifierOptions{
+		CertificateValidator: NewCertificateValidator(CertificateValidatorOptions{
+			Roots: []*x509.Certificate{root.Certificate},
+		}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	verification, err := verifier.Verify(newTestVerifierContext(testCaseDocument), testCaseDocument.FindElement("//Security"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(verification.CertificateChain) != 2 || !verification.CertificateChain[1].Equal(root.Certificate) {
+		t.Fatal("MessageVerification.CertificateChain is not the validated chain")
+	}
+
+	// Verify with an unrelated root
+	verifier, err = NewMessageVerifier(MessageVerifierOptions{
+		CertificateValidator: NewCertificateValidator(CertificateValidatorOptions{
+			Roots: []*x509.Certificate{other.Certificate},
+		}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = verifier.Verify(newTestVerifierContext(testCaseDocument), testCaseDocument.FindElement("//Security"))
+	var validationError *CertificateValidationError
+	if !errors.As(err, &validationError) || validationError.Reason != CertificateUntrusted {
+		t.Fatalf("MessageVerifier.Verify() error = %v; want %v", err, CertificateUntrusted)
+	}
+
+	// Verify with the message certificate trusted explicitly
+	verifier, err = NewMessageVerifier(MessageVerifierOptions{
+		InsecureSkipCertificateValidation: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	verification, err = verifier.Verify(newTestVerifierContext(testCaseDocument), testCaseDocument.FindElement("//Security"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(verification.CertificateChain) != 1 || !verification.Certificate.Equal(leaf.Certificate) {
+		t.Fatal("MessageVerification.Certificate is not the message certificate")
+	}
+}
+
+func Test_NewMessageVerifier_CertificateValidatorRequired(t *testing.T) {
+	_, err := NewMessageVerifier(MessageVerifierOptions{})
+	if err != ErrCertificateValidatorRequired {
+		t.Fatalf("NewMessageVerifier() error = %v; want %v", err, ErrCertificateValidatorRequired)
+	}
+}
+
+func appendTestWrappedSignedInfo(t *testing.T, doc *etree.Document, afterSignedInfo bool) {
+	t.Helper()
+
+	// Add an unsigned element to the document
+	context := newTestVerifierContext(doc)
+	evil := doc.FindElement("//Header").CreateElement("Evil")
+	evil.SetText("unsigned")
+	SetWsuId(context, evil, "Evil-1")
+	context.ResetIdIndex()
+
+	// Point a copy of SignedInfo to the unsigned element with a valid digest
+	signature := doc.FindElement("//Signature")
+	reference, err := NewSignatureReference(context)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = reference.LoadXml(context, signature.FindElement("SignedInfo/Reference"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	digestValue, err := digestReference(reference, evil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signedInfoEl := signature.SelectElement("SignedInfo").Copy()
+	for _, referenceEl := range signedInfoEl.SelectElements("Reference")[1:] {
+		signedInfoEl.RemoveChild(referenceEl)
+	}
+	referenceEl := signedInfoEl.SelectElement("Reference")
+	referenceEl.CreateAttr("URI", "#Evil-1")
+	referenceEl.SelectElement("DigestValue").SetText(base64.StdEncoding.EncodeToString(digestValue))
+
+	if afterSignedInfo {
+		signature.InsertChildAt(1, signedInfoEl)
+	} else {
+		signature.AddChild(signedInfoEl)
+	}
+}
+
+func Test_MessageVerifier_Verify_Errors(t *testing.T) {
+	testCaseCertificate := newTestCertificate(t, "Test Signer", nil, nil)
+
+	// Create test cases
+	testCases := []struct {
+		name   string
+		modify func(doc *etree.Document)
+		err    error
+	}{
+		{
+			name: "modified body",
+			modify: func(doc *etree.Document) {
+				doc.FindElement("//Payload").SetText("modified")
+			},
+			err: ErrDigestValueMismatch,
+		},
+		{
+			name: "modified signed info",
+			modify: func(doc *etree.Document) {
+				doc.FindElement("//SignedInfo/Reference").CreateAttr("Id", "modified")
+			},
+			err: ErrSignatureValueInvalid,
+		},
+		{
+			name: "duplicate body id",
+			modify: func(doc *etree.Document) {
+				body := doc.FindElement("//Body")
+				wrapper := doc.FindElement("//Header").CreateElement("Wrapper")
+				wrapper.AddChild(body.Copy())
+				body.FindElement("Payload").SetText("modified")
+			},
+			err: ErrDuplicateId,
+		},
+		{
+			name: "appended signed info",
+			modify: func(doc *etree.Document) {
+				appendTestWrappedSignedInfo(t, doc, false)
+			},
+			err: ErrUnexpectedChildElement,
+		},
+		{
+			name: "second signed info",
+			modify: func(doc *etree.Document) {
+				appendTestWrappedSignedInfo(t, doc, true)
+			},
+			err: ErrUnexpectedChildElement,
+		},
+		{
+			name: "missing key info",
+			modify: func(doc *etree.Document) {
+				signature := doc.FindElement("//Signature")
+				signature.RemoveChild(signature.SelectElement("KeyInfo"))
+			},
+			err: ErrX509CertificateNotAvailable,
+		},
+		{
+			name: "missing signature",
+			modify: func(doc *etree.Document) {
+				security := doc.FindElement("//Security")
+				security.RemoveChild(security.SelectElement("Signature"))
+			},
+			err: ErrSignatureNotFound,
+		},
+	}
+
+	verifier, err := NewMessageVerifier(MessageVerifierOptions{
+		CertificateValidator: NewCertificateValidator(CertificateValidatorOptions{
+			Roots: []*x509.Certificate{testCaseCertificate.Certificate},
+		}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range testCases {
+		// Prepare the test case
+		testCaseDocument := newTestSignedSoapEnvelope(t, testCaseCertificate)
+		tc.modify(testCaseDocument)
+
+		// Verify the test case document
+		_, err := verifier.Verify(newTestVerifierContext(testCaseDocument), testCaseDocument.FindElement("//Security"))
+		if !errors.Is(err, tc.err) {
+			t.Fatalf("MessageVerifier.Verify(%s) error = %v; want %v", tc.name, err, tc.err)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package xmlsecurity
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/beevik/etree"
+	"github.com/deb-ict/go-xml"
+)
+
+const (
+	XmlNamespace   string = "http://www.w3.org/XML/1998/namespace"
+	XmlnsNamespace string = "http://www.w3.org/2000/xmlns/"
+)
+
+func lookupNamespaceUri(el *etree.Element, prefix string) (string, bool) {
+	switch prefix {
+	case "xml":
+		return XmlNamespace, true
+	case "xmlns":
+		return XmlnsNamespace, true
+	}
+
+	for current := el; current != nil; current = current.Parent() {
+		for _, attr := range current.Attr {
+			if prefix == "" && attr.Space == "" && attr.Key == "xmlns" {
+				return attr.Value, true
+			}
+			if prefix != "" && attr.Space == "xmlns" && attr.Key == prefix {
+				return attr.Value, true
+			}
+		}
+	}
+	return "", false
+}
+
+func lookupNamespacePrefix(el *etree.Element, namespaceUri string) (string, bool) {
+	if namespaceUri == XmlNamespace {
+		return "xml", true
+	}
+
+	for current := el; current != nil; current = current.Parent() {
+		for _, attr := range current.Attr {
+			if attr.Space != "xmlns" || attr.Value != namespaceUri {
+				continue
+			}
+			// The declaration may be shadowed by a closer one
+			if uri, _ := lookupNamespaceUri(el, attr.Key); uri == namespaceUri {
+				return attr.Key, true
+			}
+		}
+	}
+	return "", false
+}
+
+func declareNamespacePrefix(el *etree.Element, prefix string, namespaceUri string) string {
+	if prefix, found := lookupNamespacePrefix(el, namespaceUri); found {
+		return prefix
+	}
+
+	// Pick a fresh prefix when the preferred one is bound to another namespace
+	candidate := prefix
+	for i := 1; ; i++ {
+		if _, found := lookupNamespaceUri(el, candidate); !found {
+			break
+		}
+		candidate = prefix + strconv.Itoa(i)
+	}
+	el.CreateAttr("xmlns:"+candidate, namespaceUri)
+	return candidate
+}
+
+func attrNamespaceUri(context xml.Context, el *etree.Element, attr *etree.Attr) string {
+	if attr.Space == "" {
+		return ""
+	}
+	if uri, found := lookupNamespaceUri(el, attr.Space); found {
+		return uri
+	}
+
+	// Detached elements may use the context prefix without declaring it
+	if context == nil {
+		return ""
+	}
+	return context.GetNamespaceUri(attr.Space)
+}
+
+func elementNamespaceUri(context xml.Context, el *etree.Element) string {
+	if uri, found := lookupNamespaceUri(el, el.Space); found {
+		return uri
+	}
+
+	// Detached elements may use the context prefix without declaring it
+	if context == nil {
+		return ""
+	}
+	return context.GetNamespaceUri(el.Space)
+}
+
+func declareContextNamespaces(context xml.Context, el *etree.Element) {
+	used := make(map[string]bool)
+	collectNamespacePrefixes(el, used)
+	prefixes := make([]string, 0, len(used))
+	for prefix := range used {
+		prefixes = append(prefixes, prefix)
+	}
+	sort.Strings(prefixes)
+
+	for _, prefix := range prefixes {
+		// Prefixes unknown to the context resolve to themselves
+		namespaceUri := context.GetNamespaceUri(prefix)
+		if namespaceUri == prefix {
+			continue
+		}
+		if uri, found := lookupNamespaceUri(el, prefix); found && uri == namespaceUri {
+			continue
+		}
+		el.CreateAttr("xmlns:"+prefix, namespaceUri)
+	}
+}
+
+func collectNamespacePrefixes(el *etree.Element, prefixes map[string]bool) {
+	if el.Space != "" {
+		prefixes[el.Space] = true
+	}
+	for _, attr := range el.Attr {
+		if attr.Space != "" && attr.Space != "xmlns" && attr.Space != "xml" {
+			prefixes[attr.Space] = true
+		}
+	}
+	for _, child := range el.ChildElements() {
+		collectNamespacePrefixes(child, prefixes)
+	}
+}
+
+func containsElement(ancestor *etree.Element, el *etree.Element) bool {
+	for current := el; current != nil; current = current.Parent() {
+		if current == ancestor {
+			return true
+		}
+	}
+	return false
+}
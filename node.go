@@ -0,0 +1,110 @@
+package xmlsecurity
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+
+	"github.com/beevik/etree"
+	"github.com/deb-ict/go-xml"
+)
+
+var (
+	ErrUnexpectedChildElement = errors.New("unexpected child element")
+)
+
+type rawNode struct {
+	Id      string
+	Element *etree.Element
+}
+
+func loadNode(context xml.Context, el *etree.Element) (xml.Node, error) {
+	namespaceUri := elementNamespaceUri(context, el)
+	typeConstructor, err := context.GetTypeConstructor(namespaceUri, el.Tag)
+	if err != nil {
+		return nil, err
+	}
+
+	node, err := typeConstructor(context)
+	if err != nil {
+		return nil, err
+	}
+	err = node.LoadXml(context, el)
+	if err != nil {
+		return nil, err
+	}
+
+	return node, nil
+}
+
+func (node *rawNode) GetId() string {
+	return node.Id
+}
+
+func (node *rawNode) LoadXml(context xml.Context, el *etree.Element) error {
+	if el == nil {
+		return xml.ErrElementIsNil
+	}
+	node.Id = GetWsuId(context, el)
+	node.Element = el.Copy()
+	return nil
+}
+
+func (node *rawNode) GetXml(context xml.Context) (*etree.Element, error) {
+	if node.Element == nil {
+		return nil, xml.ErrElementIsNil
+	}
+	return node.Element.Copy(), nil
+}
+
+func selectNamespaceAttr(el *etree.Element, namespaceUri string, key string) *etree.Attr {
+	for i := range el.Attr {
+		attr := &el.Attr[i]
+		if attr.Key != key || attr.Space == "" || attr.Space == "xmlns" {
+			continue
+		}
+		if uri, found := lookupNamespaceUri(el, attr.Space); found && uri == namespaceUri {
+			return attr
+		}
+	}
+	return nil
+}
+
+func decodeBase64Text(value string) ([]byte, error) {
+	// Base64 content may be wrapped over several lines
+	value = strings.Join(strings.Fields(value), "")
+	return base64.StdEncoding.DecodeString(value)
+}
+
+func isElement(context xml.Context, el *etree.Element, tag string, namespaceUri string) bool {
+	return el.Tag == tag && elementNamespaceUri(context, el) == namespaceUri
+}
+
+type elementSequence struct {
+	context  xml.Context
+	children []*etree.Element
+	position int
+}
+
+func newElementSequence(context xml.Context, el *etree.Element) *elementSequence {
+	return &elementSequence{
+		context:  context,
+		children: el.ChildElements(),
+	}
+}
+
+func (sequence *elementSequence) next(tag string, namespaceUri string) *etree.Element {
+	if sequence.position >= len(sequence.children) {
+		return nil
+	}
+	child := sequence.children[sequence.position]
+	if !isElement(sequence.context, child, tag, namespaceUri) {
+		return nil
+	}
+	sequence.position++
+	return child
+}
+
+func (sequence *elementSequence) done() bool {
+	return sequence.position >= len(sequence.children)
+}
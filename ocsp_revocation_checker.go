@@ -0,0 +1,366 @@
+package xmlsecurity
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ocsp"
+)
+
+type OcspFailurePolicy int
+
+const (
+	OcspHardFail OcspFailurePolicy = iota
+	OcspSoftFail
+)
+
+var (
+	ErrOcspNonceMismatch        = errors.New("ocsp nonce mismatch")
+	ErrOcspNoResponder          = errors.New("no ocsp responder available")
+	ErrOcspResponderUnavailable = errors.New("ocsp responder unavailable")
+
+	oidOcspNonce = asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 48, 1, 2}
+	oidOcspBasic = asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 48, 1, 1}
+)
+
+type OcspTransport interface {
+	Send(server string, request []byte) ([]byte, error)
+}
+
+type OcspTransportFunc func(server string, request []byte) ([]byte, error)
+
+type OcspRevocationCheckerOptions struct {
+	Transport OcspTransport
+	Clock     Clock
+	Policy    OcspFailurePolicy
+	UseNonce  bool
+	Servers   []string
+	Skew      time.Duration
+}
+
+type OcspRevocationChecker interface {
+	RevocationChecker
+	ClearCache()
+}
+
+type ocspRevocationChecker struct {
+	mutex     sync.Mutex
+	transport OcspTransport
+	clock     Clock
+	policy    OcspFailurePolicy
+	useNonce  bool
+	servers   []string
+	skew      time.Duration
+	cache     map[string]*ocsp.Response
+}
+
+type httpOcspTransport struct {
+	client *http.Client
+}
+
+type ocspRequestASN1 struct {
+	TbsRequest ocspTbsRequest
+}
+
+type ocspTbsRequest struct {
+	Version           int           `asn1:"explicit,tag:0,default:0,optional"`
+	RequestorName     asn1.RawValue `asn1:"explicit,tag:1,optional"`
+	RequestList       []asn1.RawValue
+	RequestExtensions []pkix.Extension `asn1:"explicit,tag:2,optional"`
+}
+
+type ocspResponseASN1 struct {
+	Status   asn1.Enumerated
+	Response ocspResponseBytes `asn1:"explicit,tag:0,optional"`
+}
+
+type ocspResponseBytes struct {
+	ResponseType asn1.ObjectIdentifier
+	Response     []byte
+}
+
+type ocspBasicResponse struct {
+	TbsResponseData    ocspResponseData
+	SignatureAlgorithm pkix.AlgorithmIdentifier
+	Signature          asn1.BitString
+	Certificates       []asn1.RawValue `asn1:"explicit,tag:0,optional"`
+}
+
+type ocspResponseData struct {
+	Raw                asn1.RawContent
+	Version            int `asn1:"optional,default:0,explicit,tag:0"`
+	RawResponderID     asn1.RawValue
+	ProducedAt         time.Time `asn1:"generalized"`
+	Responses          []asn1.RawValue
+	ResponseExtensions []pkix.Extension `asn1:"explicit,tag:1,optional"`
+}
+
+func NewOcspRevocationChecker(options OcspRevocationCheckerOptions) OcspRevocationChecker {
+	transport := options.Transport
+	if transport == nil {
+		transport = NewHttpOcspTransport(nil)
+	}
+	clock := options.Clock
+	if clock == nil {
+		clock = SystemClock
+	}
+
+	return &ocspRevocationChecker{
+		transport: transport,
+		clock:     clock,
+		policy:    options.Policy,
+		useNonce:  options.UseNonce,
+		servers:   options.Servers,
+		skew:      options.Skew,
+		cache:     make(map[string]*ocsp.Response),
+	}
+}
+
+func NewHttpOcspTransport(client *http.Client) OcspTransport {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &httpOcspTransport{
+		client: client,
+	}
+}
+
+func (f OcspTransportFunc) Send(server string, request []byte) ([]byte, error) {
+	return f(server, request)
+}
+
+func (transport *httpOcspTransport) Send(server string, request []byte) ([]byte, error) {
+	response, err := transport.client.Post(server, "application/ocsp-request", bytes.NewReader(request))
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("ocsp responder returned %s", response.Status)
+	}
+	return io.ReadAll(io.LimitReader(response.Body, 1024*1024))
+}
+
+func (checker *ocspRevocationChecker) ClearCache() {
+	checker.mutex.Lock()
+	defer checker.mutex.Unlock()
+
+	checker.cache = make(map[string]*ocsp.Response)
+}
+
+func (checker *ocspRevocationChecker) CheckRevocation(certificate *x509.Certificate, issuer *x509.Certificate) error {
+	if certificate == nil || issuer == nil {
+		return checker.fail(ErrRevocationStatusUnknown)
+	}
+
+	response, err := checker.getResponse(certificate, issuer)
+	if err != nil {
+		return checker.fail(err)
+	}
+
+	switch response.Status {
+	case ocsp.Good:
+		return nil
+	case ocsp.Revoked:
+		return ErrCertificateRevoked
+	default:
+		return checker.fail(ErrRevocationStatusUnknown)
+	}
+}
+
+func (checker *ocspRevocationChecker) fail(err error) error {
+	// Soft fail only covers responders that cannot be reached or give no answer, an unknown status is an answer
+	if checker.policy == OcspSoftFail && isOcspUnavailable(err) {
+		return nil
+	}
+	if errors.Is(err, ErrRevocationStatusUnknown) {
+		return err
+	}
+	return fmt.Errorf("%w: %w", ErrRevocationStatusUnknown, err)
+}
+
+func (checker *ocspRevocationChecker) getResponse(certificate *x509.Certificate, issuer *x509.Certificate) (*ocsp.Response, error) {
+	key := ocspCacheKey(certificate, issuer)
+	now := checker.clock.Now()
+
+	checker.mutex.Lock()
+	cached, found := checker.cache[key]
+	checker.mutex.Unlock()
+	if found && now.Before(cached.NextUpdate) {
+		return cached, nil
+	}
+
+	servers := checker.servers
+	if len(servers) == 0 {
+		servers = certificate.OCSPServer
+	}
+	if len(servers) == 0 {
+		return nil, ErrOcspNoResponder
+	}
+
+	var lastErr error
+	for _, server := range servers {
+		response, err := checker.query(server, certificate, issuer, now)
+		if err != nil {
+			// An invalid response is reported over an unavailable responder
+			if lastErr == nil || isOcspUnavailable(lastErr) {
+				lastErr = err
+			}
+			continue
+		}
+
+		// Responses without a next update carry no caching hint
+		if !response.NextUpdate.IsZero() {
+			checker.mutex.Lock()
+			checker.cache[key] = response
+			checker.mutex.Unlock()
+		}
+		return response, nil
+	}
+	return nil, lastErr
+}
+
+func (checker *ocspRevocationChecker) query(server string, certificate *x509.Certificate, issuer *x509.Certificate, now time.Time) (*ocsp.Response, error) {
+	request, err := ocsp.CreateRequest(certificate, issuer, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var nonce []byte
+	if checker.useNonce {
+		nonce = make([]byte, 16)
+		_, err = rand.Read(nonce)
+		if err != nil {
+			return nil, err
+		}
+		request, err = addOcspRequestNonce(request, nonce)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	data, err := checker.transport.Send(server, request)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrOcspResponderUnavailable, err)
+	}
+	response, err := ocsp.ParseResponseForCert(data, certificate, issuer)
+	var responseError ocsp.ResponseError
+	if errors.As(err, &responseError) && (responseError.Status == ocsp.TryLater || responseError.Status == ocsp.InternalError) {
+		return nil, fmt.Errorf("%w: %w", ErrOcspResponderUnavailable, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// A delegated responder must be explicitly authorized for OCSP signing
+	if response.Certificate != nil && !response.Certificate.Equal(issuer) && !isOcspSigningCertificate(response.Certificate) {
+		return nil, errors.New("ocsp responder certificate not authorized for ocsp signing")
+	}
+	if response.ThisUpdate.After(now.Add(checker.skew)) {
+		return nil, errors.New("ocsp response is not yet valid")
+	}
+	if !response.NextUpdate.IsZero() && now.Add(-checker.skew).After(response.NextUpdate) {
+		return nil, errors.New("ocsp response has expired")
+	}
+
+	if nonce != nil {
+		responseNonce, err := getOcspResponseNonce(data)
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(responseNonce, nonce) {
+			return nil, ErrOcspNonceMismatch
+		}
+	}
+
+	return response, nil
+}
+
+func addOcspRequestNonce(request []byte, nonce []byte) ([]byte, error) {
+	var ocspRequest ocspRequestASN1
+	_, err := asn1.Unmarshal(request, &ocspRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := asn1.Marshal(nonce)
+	if err != nil {
+		return nil, err
+	}
+	ocspRequest.TbsRequest.RequestExtensions = append(ocspRequest.TbsRequest.RequestExtensions, pkix.Extension{
+		Id:    oidOcspNonce,
+		Value: value,
+	})
+	return asn1.Marshal(ocspRequest)
+}
+
+func getOcspResponseNonce(data []byte) ([]byte, error) {
+	var response ocspResponseASN1
+	_, err := asn1.Unmarshal(data, &response)
+	if err != nil {
+		return nil, err
+	}
+	if !response.Response.ResponseType.Equal(oidOcspBasic) {
+		return nil, errors.New("bad ocsp response type")
+	}
+
+	var basicResponse ocspBasicResponse
+	_, err = asn1.Unmarshal(response.Response.Response, &basicResponse)
+	if err != nil {
+		return nil, err
+	}
+	for _, extension := range basicResponse.TbsResponseData.ResponseExtensions {
+		if !extension.Id.Equal(oidOcspNonce) {
+			continue
+		}
+
+		var nonce []byte
+		_, err = asn1.Unmarshal(extension.Value, &nonce)
+		if err != nil {
+			return nil, err
+		}
+		return nonce, nil
+	}
+
+	return nil, ErrOcspNonceMismatch
+}
+
+func ocspCacheKey(certificate *x509.Certificate, issuer *x509.Certificate) string {
+	issuerHash := sha1.Sum(issuer.Raw)
+	return hex.EncodeToString(issuerHash[:]) + ":" + certificate.SerialNumber.String()
+}
+
+func isOcspUnavailable(err error) bool {
+	return errors.Is(err, ErrOcspNoResponder) || errors.Is(err, ErrOcspResponderUnavailable)
+}
+
+func isOcspSigningCertificate(certificate *x509.Certificate) bool {
+	// RFC 6960 requires id-kp-OCSPSigning, anyExtendedKeyUsage does not authorize a responder
+	for _, extKeyUsage := range certificate.ExtKeyUsage {
+		if extKeyUsage == x509.ExtKeyUsageOCSPSigning {
+			return true
+		}
+	}
+	return false
+}
+
+func hasExtKeyUsage(certificate *x509.Certificate, usage x509.ExtKeyUsage) bool {
+	for _, extKeyUsage := range certificate.ExtKeyUsage {
+		if extKeyUsage == usage || extKeyUsage == x509.ExtKeyUsageAny {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,349 @@
+package xmlsecurity
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ocsp"
+)
+
+type testOcspResponder struct {
+	t          *testing.T
+	issuer     *testCertificate
+	status     int
+	nextUpdate time.Duration
+	echoNonce  bool
+	requests   int
+	responder  *testCertificate
+	err        error
+}
+
+func (responder *testOcspResponder) Send(server string, request []byte) ([]byte, error) {
+	responder.t.Helper()
+	responder.requests++
+	if responder.err != nil {
+		return nil, responder.err
+	}
+
+	parsedRequest, err := ocsp.ParseRequest(request)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	template := ocsp.Response{
+		Status:       responder.status,
+		SerialNumber: parsedRequest.SerialNumber,
+		ThisUpdate:   now.Add(-time.Minute),
+		RevokedAt:    now.Add(-time.Hour),
+	}
+	if responder.nextUpdate != 0 {
+		template.NextUpdate = now.Add(responder.nextUpdate)
+	}
+	signer := responder.issuer
+	if responder.responder != nil {
+		signer = responder.responder
+		template.Certificate = signer.Certificate
+	}
+	data, err := ocsp.CreateResponse(responder.issuer.Certificate, signer.Certificate, template, signer.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	if !responder.echoNonce {
+		return data, nil
+	}
+
+	// Echo the request nonce in the response extensions
+	var ocspRequest ocspRequestASN1
+	_, err = asn1.Unmarshal(request, &ocspRequest)
+	if err != nil {
+		return nil, err
+	}
+	for _, extension := range ocspRequest.TbsRequest.RequestExtensions {
+		if extension.Id.Equal(oidOcspNonce) {
+			return resignTestOcspResponse(responder.t, data, signer.PrivateKey, extension), nil
+		}
+	}
+	return data, nil
+}
+
+func resignTestOcspResponse(t *testing.T, data []byte, signer crypto.Signer, extensions ...pkix.Extension) []byte {
+	t.Helper()
+
+	var response ocspResponseASN1
+	_, err := asn1.Unmarshal(data, &response)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var basicResponse ocspBasicResponse
+	_, err = asn1.Unmarshal(response.Response.Response, &basicResponse)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	basicResponse.TbsResponseData.Raw = nil
+	basicResponse.TbsResponseData.ResponseExtensions = extensions
+	tbsResponseData, err := asn1.Marshal(basicResponse.TbsResponseData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest := sha256.Sum256(tbsResponseData)
+	signature, err := signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	basicResponse.Signature = asn1.BitString{Bytes: signature, BitLength: len(signature) * 8}
+
+	response.Response.Response, err = asn1.Marshal(basicResponse)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := asn1.Marshal(response)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+func Test_OcspRevocationChecker_CheckRevocation(t *testing.T) {
+	_, intermediate, leaf := newTestCertificateHierarchy(t, nil)
+
+	// Create test case
+	testCase := []struct {
+		name     string
+		status   int
+		policy   OcspFailurePolicy
+		expected error
+	}{
+		{name: "good", status: ocsp.Good, expected: nil},
+		{name: "revoked", status: ocsp.Revoked, expected: ErrCertificateRevoked},
+		{name: "revoked soft fail", status: ocsp.Revoked, policy: OcspSoftFail, expected: ErrCertificateRevoked},
+		{name: "unknown", status: ocsp.Unknown, expected: ErrRevocationStatusUnknown},
+		{name: "unknown soft fail", status: ocsp.Unknown, policy: OcspSoftFail, expected: ErrRevocationStatusUnknown},
+	}
+
+	for _, tc := range testCase {
+		responder := &testOcspResponder{t: t, issuer: intermediate, status: tc.status, nextUpdate: time.Hour}
+		checker := NewOcspRevocationChecker(OcspRevocationCheckerOptions{
+			Transport: responder,
+			Policy:    tc.policy,
+			Servers:   []string{"http://ocsp.example.com"},
+		})
+
+		err := checker.CheckRevocation(leaf.Certificate, intermediate.Certificate)
+		if !errors.Is(err, tc.expected) || (tc.expected == nil && err != nil) {
+			t.Fatalf("%s: OcspRevocationChecker.CheckRevocation() = %v; want %v", tc.name, err, tc.expected)
+		}
+	}
+}
+
+func Test_OcspRevocationChecker_Cache(t *testing.T) {
+	_, intermediate, leaf := newTestCertificateHierarchy(t, nil)
+
+	now := time.Now()
+	responder := &testOcspResponder{t: t, issuer: intermediate, status: ocsp.Good, nextUpdate: time.Hour}
+	checker := NewOcspRevocationChecker(OcspRevocationCheckerOptions{
+		Transport: responder,
+		Clock:     ClockFunc(func() time.Time { return now }),
+		Servers:   []string{"http://ocsp.example.com"},
+	})
+
+	// The second check is served from the cache
+	for i := 0; i < 2; i++ {
+		err := checker.CheckRevocation(leaf.Certificate, intermediate.Certificate)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if responder.requests != 1 {
+		t.Fatalf("responder.requests = %d; want 1", responder.requests)
+	}
+
+	// Responses past their next update are fetched again
+	now = now.Add(2 * time.Hour)
+	responder.nextUpdate = 3 * time.Hour
+	err := checker.CheckRevocation(leaf.Certificate, intermediate.Certificate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if responder.requests != 2 {
+		t.Fatalf("responder.requests = %d; want 2", responder.requests)
+	}
+}
+
+func Test_OcspRevocationChecker_Nonce(t *testing.T) {
+	_, intermediate, leaf := newTestCertificateHierarchy(t, nil)
+
+	// A responder that echoes the nonce is accepted
+	responder := &testOcspResponder{t: t, issuer: intermediate, status: ocsp.Good, echoNonce: true}
+	checker := NewOcspRevocationChecker(OcspRevocationCheckerOptions{
+		Transport: responder,
+		UseNonce:  true,
+		Servers:   []string{"http://ocsp.example.com"},
+	})
+	err := checker.CheckRevocation(leaf.Certificate, intermediate.Certificate)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A responder that ignores the nonce is rejected
+	responder.echoNonce = false
+	err = checker.CheckRevocation(leaf.Certificate, intermediate.Certificate)
+	if !errors.Is(err, ErrRevocationStatusUnknown) || !errors.Is(err, ErrOcspNonceMismatch) {
+		t.Fatalf("OcspRevocationChecker.CheckRevocation() = %v; want %v", err, ErrRevocationStatusUnknown)
+	}
+}
+
+func Test_OcspRevocationChecker_HttpTransport(t *testing.T) {
+	root, intermediate, leaf := newTestCertificateHierarchy(t, nil)
+	responder := &testOcspResponder{t: t, issuer: intermediate, status: ocsp.Revoked}
+
+	// Create the test case responder
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/ocsp-request" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		request, err := io.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		response, err := responder.Send(r.URL.String(), request)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/ocsp-response")
+		_, _ = w.Write(response)
+	}))
+	defer server.Close()
+	leafWithResponder := newTestCertificate(t, "Test Leaf", intermediate, func(template *x509.Certificate) {
+		template.OCSPServer = []string{server.URL}
+	})
+
+	// Validate the test case certificate
+	checker := NewOcspRevocationChecker(OcspRevocationCheckerOptions{
+		Transport: NewHttpOcspTransport(server.Client()),
+	})
+	validator := NewCertificateValidator(CertificateValidatorOptions{
+		Roots:              []*x509.Certificate{root.Certificate},
+		Intermediates:      []*x509.Certificate{intermediate.Certificate},
+		RevocationCheckers: []RevocationChecker{checker},
+	})
+	token, err := NewX509BinarySecurityToken(nil, leafWithResponder.Certificate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = validator.ValidateProvider(nil, token)
+	var validationError *CertificateValidationError
+	if !errors.As(err, &validationError) || validationError.Reason != CertificateRevoked {
+		t.Fatalf("CertificateValidator.ValidateProvider() = %v; want %s", err, CertificateRevoked)
+	}
+
+	// Certificates without a responder cannot be checked
+	err = checker.CheckRevocation(leaf.Certificate, intermediate.Certificate)
+	if !errors.Is(err, ErrRevocationStatusUnknown) {
+		t.Fatalf("OcspRevocationChecker.CheckRevocation() = %v; want %v", err, ErrRevocationStatusUnknown)
+	}
+}
+
+func Test_OcspRevocationChecker_DelegatedResponder(t *testing.T) {
+	_, intermediate, leaf := newTestCertificateHierarchy(t, nil)
+
+	// Create test case
+	testCase := []struct {
+		name         string
+		extKeyUsages []x509.ExtKeyUsage
+		expected     error
+	}{
+		{name: "ocsp signing", extKeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageOCSPSigning}, expected: nil},
+		{name: "any", extKeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageAny}, expected: ErrRevocationStatusUnknown},
+		{name: "none", expected: ErrRevocationStatusUnknown},
+	}
+
+	for _, tc := range testCase {
+		delegate := newTestCertificate(t, "Test Responder", intermediate, func(template *x509.Certificate) {
+			template.ExtKeyUsage = tc.extKeyUsages
+		})
+		responder := &testOcspResponder{t: t, issuer: intermediate, responder: delegate, status: ocsp.Good}
+		checker := NewOcspRevocationChecker(OcspRevocationCheckerOptions{
+			Transport: responder,
+			Servers:   []string{"http://ocsp.example.com"},
+		})
+
+		err := checker.CheckRevocation(leaf.Certificate, intermediate.Certificate)
+		if !errors.Is(err, tc.expected) || (tc.expected == nil && err != nil) {
+			t.Fatalf("%s: OcspRevocationChecker.CheckRevocation() = %v; want %v", tc.name, err, tc.expected)
+		}
+	}
+}
+
+func Test_OcspRevocationChecker_SoftFail(t *testing.T) {
+	_, intermediate, leaf := newTestCertificateHierarchy(t, nil)
+	other := newTestCertificate(t, "Other Root", nil, nil)
+
+	// Create test case
+	testCase := []struct {
+		name      string
+		responder *testOcspResponder
+		useNonce  bool
+		expected  error
+	}{
+		{
+			name:      "transport error",
+			responder: &testOcspResponder{t: t, issuer: intermediate, err: errors.New("connection refused")},
+			expected:  nil,
+		},
+		{
+			name:      "nonce mismatch",
+			responder: &testOcspResponder{t: t, issuer: intermediate, status: ocsp.Good},
+			useNonce:  true,
+			expected:  ErrOcspNonceMismatch,
+		},
+		{
+			name:      "invalid signature",
+			responder: &testOcspResponder{t: t, issuer: intermediate, responder: other, status: ocsp.Good},
+			expected:  ErrRevocationStatusUnknown,
+		},
+		{
+			name:      "unknown status",
+			responder: &testOcspResponder{t: t, issuer: intermediate, status: ocsp.Unknown},
+			expected:  ErrRevocationStatusUnknown,
+		},
+	}
+
+	for _, tc := range testCase {
+		checker := NewOcspRevocationChecker(OcspRevocationCheckerOptions{
+			Transport: tc.responder,
+			Policy:    OcspSoftFail,
+			UseNonce:  tc.useNonce,
+			Servers:   []string{"http://ocsp.example.com"},
+		})
+
+		err := checker.CheckRevocation(leaf.Certificate, intermediate.Certificate)
+		if !errors.Is(err, tc.expected) || (tc.expected == nil && err != nil) {
+			t.Fatalf("%s: OcspRevocationChecker.CheckRevocation() = %v; want %v", tc.name, err, tc.expected)
+		}
+	}
+
+	// Certificates without a responder are not checked
+	checker := NewOcspRevocationChecker(OcspRevocationCheckerOptions{
+		Transport: &testOcspResponder{t: t, issuer: intermediate},
+		Policy:    OcspSoftFail,
+	})
+	err := checker.CheckRevocation(leaf.Certificate, intermediate.Certificate)
+	if err != nil {
+		t.Fatalf("OcspRevocationChecker.CheckRevocation() = %v; want nil", err)
+	}
+}
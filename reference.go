@@ -3,7 +3,6 @@ package xmlsecurity
 import (
 	"crypto/x509"
 	"errors"
-	"strings"
 
 	"github.com/beevik/etree"
 	"github.com/deb-ict/go-xml"
@@ -12,6 +11,7 @@ import (
 type Reference interface {
 	xml.Node
 	X509CertificateProvider
+	X509ChainProvider
 	GetUri() string
 	SetUri(uri string)
 	GetValueType() string
@@ -48,35 +48,51 @@ func (node *reference) SetValueType(valueType string) {
 }
 
 func (node *reference) GetX509Certificate(context xml.Context) (*x509.Certificate, error) {
-	if strings.HasPrefix(node.GetUri(), "#") {
-		uri := node.GetUri()[1:]
-		ref := context.GetDocument().FindElement("@Id='" + uri + "'")
-		if ref == nil {
-			return nil, errors.New("reference not found")
-		}
-
-		refConstructor, err := context.GetTypeConstructor(ref.Space, ref.Tag)
-		if err != nil {
-			return nil, err
-		}
-		refNode, err := refConstructor(context)
-		if err != nil {
-			return nil, err
-		}
-		err = refNode.LoadXml(context, ref)
-		if err != nil {
-			return nil, err
-		}
-
-		provider, ok := refNode.(X509CertificateProvider)
-		if !ok {
-			return nil, errors.New("reference not a X509CertificateProvider")
-		}
-		return provider.GetX509Certificate(context)
-
-	} else {
-		return nil, errors.New("unsupported URI format")
+	context, refNode, err := node.resolve(context)
+	if err != nil {
+		return nil, err
+	}
+
+	provider, ok := refNode.(X509CertificateProvider)
+	if !ok {
+		return nil, errors.New("reference not a X509CertificateProvider")
+	}
+	return provider.GetX509Certificate(context)
+}
+
+func (node *reference) GetX509CertificateChain(context xml.Context) ([]*x509.Certificate, error) {
+	context, refNode, err := node.resolve(context)
+	if err != nil {
+		return nil, err
+	}
+
+	_, isChainProvider := refNode.(X509ChainProvider)
+	_, isProvider := refNode.(X509CertificateProvider)
+	if !isChainProvider && !isProvider {
+		return nil, errors.New("reference not a X509CertificateProvider")
+	}
+	return getX509CertificateChain(context, refNode)
+}
+
+func (node *reference) resolve(context xml.Context) (xml.Context, xml.Node, error) {
+	context, err := enterReference(context, node.GetUri())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	index, err := GetIdIndex(context)
+	if err != nil {
+		return nil, nil, err
+	}
+	ref, err := index.ResolveUri(node.GetUri())
+	if err != nil {
+		return nil, nil, err
+	}
+	refNode, err := loadNode(context, ref)
+	if err != nil {
+		return nil, nil, err
 	}
+	return context, refNode, nil
 }
 
 func (node *reference) LoadXml(context xml.Context, el *etree.Element) error {
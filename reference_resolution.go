@@ -0,0 +1,73 @@
+package xmlsecurity
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/deb-ict/go-xml"
+)
+
+const (
+	DefaultMaxReferenceDepth int = 8
+)
+
+var (
+	ErrReferenceCycle         = errors.New("circular reference")
+	ErrReferenceDepthExceeded = errors.New("reference depth exceeded")
+)
+
+type ReferenceResolutionError struct {
+	Uri  string
+	Path []string
+	Err  error
+}
+
+func (e *ReferenceResolutionError) Error() string {
+	path := strings.Join(e.Path, " -> ")
+	if path != "" {
+		path += " -> "
+	}
+	return e.Err.Error() + ": " + path + e.Uri
+}
+
+func (e *ReferenceResolutionError) Unwrap() error {
+	return e.Err
+}
+
+type referenceResolutionContext struct {
+	Context
+	path []string
+}
+
+func GetMaxReferenceDepth(context xml.Context) int {
+	securityContext, ok := context.(Context)
+	if !ok || securityContext.GetMaxReferenceDepth() <= 0 {
+		return DefaultMaxReferenceDepth
+	}
+	return securityContext.GetMaxReferenceDepth()
+}
+
+func enterReference(context xml.Context, uri string) (xml.Context, error) {
+	var path []string
+	if resolutionContext, ok := context.(*referenceResolutionContext); ok {
+		context = resolutionContext.Context
+		path = resolutionContext.path
+	}
+
+	for _, visited := range path {
+		if visited == uri {
+			return nil, &ReferenceResolutionError{Uri: uri, Path: path, Err: ErrReferenceCycle}
+		}
+	}
+	if len(path) >= GetMaxReferenceDepth(context) {
+		return nil, &ReferenceResolutionError{Uri: uri, Path: path, Err: ErrReferenceDepthExceeded}
+	}
+
+	// Each resolution step gets its own copy so sibling references do not see each other
+	nextPath := make([]string, len(path), len(path)+1)
+	copy(nextPath, path)
+	return &referenceResolutionContext{
+		Context: NewContext(context),
+		path:    append(nextPath, uri),
+	}, nil
+}
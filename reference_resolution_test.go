@@ -0,0 +1,101 @@
+package xmlsecurity
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/beevik/etree"
+	"github.com/deb-ict/go-xml"
+)
+
+func Test_Reference_GetX509Certificate_Cycle(t *testing.T) {
+	// Create test cases
+	testCases := []string{
+		`<wsse:SecurityTokenReference wsu:Id="a"><wsse:Reference URI="#a"/></wsse:SecurityTokenReference>`,
+		`<wsse:SecurityTokenReference wsu:Id="a"><wsse:Reference URI="#b"/></wsse:SecurityTokenReference>` +
+			`<wsse:SecurityTokenReference wsu:Id="b"><wsse:Reference URI="#a"/></wsse:SecurityTokenReference>`,
+	}
+
+	for _, tc := range testCases {
+		// Prepare the test case
+		testCaseContext, testCaseReference := newTestReferenceChain(t, tc, "#a")
+
+		// Resolve the test case Reference
+		_, err := testCaseReference.GetX509Certificate(testCaseContext)
+		var resolutionErr *ReferenceResolutionError
+		if !errors.As(err, &resolutionErr) || !errors.Is(err, ErrReferenceCycle) {
+			t.Fatalf("Reference.GetX509Certificate() error = %v; want %v", err, ErrReferenceCycle)
+		}
+		_, err = testCaseReference.GetX509CertificateChain(testCaseContext)
+		if !errors.Is(err, ErrReferenceCycle) {
+			t.Fatalf("Reference.GetX509CertificateChain() error = %v; want %v", err, ErrReferenceCycle)
+		}
+	}
+}
+
+func Test_Reference_GetX509Certificate_DepthExceeded(t *testing.T) {
+	// Create test case XML with a chain of references ending in a BinarySecurityToken
+	certificate := newTestCertificate(t, "Test", nil, nil).Certificate
+	testCaseBinarySecurityToken, err := NewX509BinarySecurityToken(nil, certificate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var builder strings.Builder
+	for i := 0; i < 4; i++ {
+		fmt.Fprintf(&builder, `<wsse:SecurityTokenReference wsu:Id="r%d"><wsse:Reference URI="#r%d"/></wsse:SecurityTokenReference>`, i, i+1)
+	}
+	fmt.Fprintf(&builder,
+		`<wsse:BinarySecurityToken EncodingType="%s" ValueType="%s" wsu:Id="r4">%s</wsse:BinarySecurityToken>`,
+		testCaseBinarySecurityToken.GetEncodingType(),
+		testCaseBinarySecurityToken.GetValueType(),
+		testCaseBinarySecurityToken.GetValue(),
+	)
+
+	// Resolve within the default depth
+	testCaseContext, testCaseReference := newTestReferenceChain(t, builder.String(), "#r0")
+	resolved, err := testCaseReference.GetX509Certificate(testCaseContext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !resolved.Equal(certificate) {
+		t.Fatal("Reference.GetX509Certificate() did not follow the reference chain")
+	}
+
+	// Resolve with a lower depth limit
+	testCaseContext.SetMaxReferenceDepth(3)
+	_, err = testCaseReference.GetX509Certificate(testCaseContext)
+	if !errors.Is(err, ErrReferenceDepthExceeded) {
+		t.Fatalf("Reference.GetX509Certificate() error = %v; want %v", err, ErrReferenceDepthExceeded)
+	}
+}
+
+func newTestReferenceChain(t *testing.T, tokens string, uri string) (Context, Reference) {
+	t.Helper()
+
+	// Create test case XML
+	testCaseXml := fmt.Sprintf(
+		`<wsse:Security xmlns:wsse="%s" xmlns:wsu="%s">%s</wsse:Security>`,
+		WsseNamespace,
+		WsuNamespace,
+		tokens,
+	)
+
+	// Prepare the test case
+	testCaseDocument := etree.NewDocument()
+	err := testCaseDocument.ReadFromString(testCaseXml)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCaseContext := NewContext(xml.NewContext(testCaseDocument))
+	ConfigureContext(testCaseContext)
+
+	// Create test case Reference
+	testCaseReference, err := NewReference(testCaseContext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCaseReference.SetUri(uri)
+	return testCaseContext, testCaseReference
+}
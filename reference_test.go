@@ -148,3 +148,42 @@ func Test_Reference_GetXml(t *testing.T) {
 		t.Fatalf("Reference.GetXml() = %s; want %s", resultXml, testCaseXml)
 	}
 }
+
+func Test_Reference_GetX509Certificate_GeneratedId(t *testing.T) {
+	testCaseCertificate := newTestCertificate(t, "Test Signer", nil, nil)
+
+	// Prepare the test case
+	testCaseDocument := etree.NewDocument()
+	testCaseContext := NewContext(xml.NewContext(testCaseDocument))
+	ConfigureContext(testCaseContext)
+
+	// Generate the token id before the document is built
+	token, err := NewX509BinarySecurityToken(testCaseContext, testCaseCertificate.Certificate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tokenEl, err := token.GetXml(testCaseContext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	securityEl := testCaseDocument.CreateElement("wsse:Security")
+	securityEl.CreateAttr("xmlns:wsse", WsseNamespace)
+	securityEl.CreateAttr("xmlns:wsu", WsuNamespace)
+	securityEl.AddChild(tokenEl)
+
+	// Create test case Reference
+	testCaseReference, err := NewReference(testCaseContext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCaseReference.SetUri("#" + token.GetId())
+
+	// Resolve test case Reference into the built document
+	certificate, err := testCaseReference.GetX509Certificate(testCaseContext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !certificate.Equal(testCaseCertificate.Certificate) {
+		t.Fatal("Reference.GetX509Certificate() did not return the token certificate")
+	}
+}
@@ -0,0 +1,15 @@
+package xmlsecurity
+
+import (
+	"crypto/x509"
+	"errors"
+)
+
+var (
+	ErrCertificateRevoked      = errors.New("certificate revoked")
+	ErrRevocationStatusUnknown = errors.New("revocation status unknown")
+)
+
+type RevocationChecker interface {
+	CheckRevocation(certificate *x509.Certificate, issuer *x509.Certificate) error
+}
@@ -0,0 +1,179 @@
+package xmlsecurity
+
+import (
+	"github.com/beevik/etree"
+	"github.com/deb-ict/go-xml"
+)
+
+type Security interface {
+	xml.Node
+	GetMustUnderstand() bool
+	SetMustUnderstand(mustUnderstand bool)
+	GetActor() string
+	SetActor(actor string)
+	GetRole() string
+	SetRole(role string)
+	GetTokens() []xml.Node
+	SetTokens(tokens []xml.Node)
+	AddToken(token xml.Node)
+	GetTokenById(id string) xml.Node
+}
+
+type security struct {
+	MustUnderstand bool
+	Actor          string
+	Role           string
+	Tokens         []xml.Node
+}
+
+type securityTokenWithId interface {
+	GetId() string
+}
+
+func NewSecurity(context xml.Context) (Security, error) {
+	return &security{
+		Tokens: make([]xml.Node, 0),
+	}, nil
+}
+
+func NewSecurityNode(context xml.Context) (xml.Node, error) {
+	return NewSecurity(context)
+}
+
+func GetSecurityToken[T any](security Security) (T, bool) {
+	for _, token := range security.GetTokens() {
+		if result, ok := token.(T); ok {
+			return result, true
+		}
+	}
+
+	var empty T
+	return empty, false
+}
+
+func GetSecurityTokens[T any](security Security) []T {
+	result := make([]T, 0)
+	for _, token := range security.GetTokens() {
+		if item, ok := token.(T); ok {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+func (node *security) GetMustUnderstand() bool {
+	return node.MustUnderstand
+}
+
+func (node *security) SetMustUnderstand(mustUnderstand bool) {
+	node.MustUnderstand = mustUnderstand
+}
+
+func (node *security) GetActor() string {
+	return node.Actor
+}
+
+func (node *security) SetActor(actor string) {
+	node.Actor = actor
+}
+
+func (node *security) GetRole() string {
+	return node.Role
+}
+
+func (node *security) SetRole(role string) {
+	node.Role = role
+}
+
+func (node *security) GetTokens() []xml.Node {
+	return node.Tokens
+}
+
+func (node *security) SetTokens(tokens []xml.Node) {
+	node.Tokens = tokens
+}
+
+func (node *security) AddToken(token xml.Node) {
+	node.Tokens = append(node.Tokens, token)
+}
+
+func (node *security) GetTokenById(id string) xml.Node {
+	if id == "" {
+		return nil
+	}
+	for _, token := range node.Tokens {
+		tokenWithId, ok := token.(securityTokenWithId)
+		if ok && tokenWithId.GetId() == id {
+			return token
+		}
+	}
+	return nil
+}
+
+func (node *security) LoadXml(context xml.Context, el *etree.Element) error {
+	err := xml.ValidateElement(el, "Security", WsseNamespace)
+	if err != nil {
+		return err
+	}
+
+	node.SetMustUnderstand(false)
+	node.SetActor("")
+	node.SetRole("")
+	for _, namespaceUri := range []string{Soap11Namespace, Soap12Namespace} {
+		if attr := selectNamespaceAttr(el, namespaceUri, "mustUnderstand"); attr != nil {
+			node.SetMustUnderstand(attr.Value == "1" || attr.Value == "true")
+		}
+	}
+	if attr := selectNamespaceAttr(el, Soap11Namespace, "actor"); attr != nil {
+		node.SetActor(attr.Value)
+	}
+	if attr := selectNamespaceAttr(el, Soap12Namespace, "role"); attr != nil {
+		node.SetRole(attr.Value)
+	}
+
+	tokens := make([]xml.Node, 0)
+	for _, child := range el.ChildElements() {
+		token, err := loadNode(context, child)
+		if err == xml.ErrNoTypeConstructor {
+			token = &rawNode{}
+			err = token.LoadXml(context, child)
+		}
+		if err != nil {
+			return err
+		}
+		tokens = append(tokens, token)
+	}
+	node.SetTokens(tokens)
+
+	return nil
+}
+
+func (node *security) GetXml(context xml.Context) (*etree.Element, error) {
+	el := etree.NewElement("Security")
+	el.Space = context.GetNamespacePrefix(WsseNamespace)
+
+	// SOAP 1.2 headers carry a role instead of an actor
+	soapNamespace := Soap11Namespace
+	if node.GetRole() != "" && node.GetActor() == "" {
+		soapNamespace = Soap12Namespace
+	}
+	if node.GetMustUnderstand() {
+		el.CreateAttr(context.GetNamespacePrefix(soapNamespace)+":mustUnderstand", "1")
+	}
+	if node.GetActor() != "" {
+		el.CreateAttr(context.GetNamespacePrefix(Soap11Namespace)+":actor", node.GetActor())
+	}
+	if node.GetRole() != "" {
+		el.CreateAttr(context.GetNamespacePrefix(Soap12Namespace)+":role", node.GetRole())
+	}
+
+	for _, token := range node.GetTokens() {
+		tokenEl, err := token.GetXml(context)
+		if err != nil {
+			return nil, err
+		}
+		el.AddChild(tokenEl)
+	}
+
+	return el, nil
+}
@@ -0,0 +1,251 @@
+package xmlsecurity
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/beevik/etree"
+	"github.com/deb-ict/go-xml"
+)
+
+func Test_Security_LoadXml_InvalidElement(t *testing.T) {
+	// Create test case XML
+	testCaseXml := fmt.Sprintf(
+		`<wsse:InvalidTag xmlns:wsse="%s"/>`,
+		WsseNamespace,
+	)
+
+	// Prepare the test case
+	testCaseDocument := etree.NewDocument()
+	err := testCaseDocument.ReadFromString(testCaseXml)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCaseContext := xml.NewContext(testCaseDocument)
+
+	// Create test case Security
+	testCaseSecurity, err := NewSecurity(testCaseContext)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Load test case Security
+	err = testCaseSecurity.LoadXml(testCaseContext, testCaseDocument.Root())
+	if err != xml.ErrInvalidElementTag {
+		t.Fatal(err)
+	}
+}
+
+func Test_Security_LoadXml(t *testing.T) {
+	// Create test case XML
+	testCaseXml := fmt.Sprintf(
+		`<wsse:Security soap:mustUnderstand="1" soap:actor="next" xmlns:soap="%s" xmlns:wsse="%s" xmlns:wsu="%s">`+
+			`<wsse:BinarySecurityToken wsu:Id="BST-1">cert_data</wsse:BinarySecurityToken>`+
+			`<Unknown wsu:Id="U-1"/>`+
+			`<wsse:SecurityTokenReference wsu:Id="STR-1"><wsse:Reference URI="#BST-1"/></wsse:SecurityTokenReference>`+
+			`</wsse:Security>`,
+		Soap11Namespace,
+		WsseNamespace,
+		WsuNamespace,
+	)
+
+	// Prepare the test case
+	testCaseDocument := etree.NewDocument()
+	err := testCaseDocument.ReadFromString(testCaseXml)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCaseContext := xml.NewContext(testCaseDocument)
+	ConfigureContext(testCaseContext)
+
+	// Create test case Security
+	testCaseSecurity, err := NewSecurity(testCaseContext)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Load test case Security
+	err = testCaseSecurity.LoadXml(testCaseContext, testCaseDocument.Root())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Validate test case Security
+	if !testCaseSecurity.GetMustUnderstand() {
+		t.Fatal("Security.MustUnderstand = false; want true")
+	}
+	if testCaseSecurity.GetActor() != "next" {
+		t.Fatalf("Security.Actor = %s; want next", testCaseSecurity.GetActor())
+	}
+	if len(testCaseSecurity.GetTokens()) != 3 {
+		t.Fatalf("len(Security.Tokens) = %d; want 3", len(testCaseSecurity.GetTokens()))
+	}
+	if _, ok := testCaseSecurity.GetTokens()[0].(BinarySecurityToken); !ok {
+		t.Fatalf("Security.Tokens[0] = %T; want BinarySecurityToken", testCaseSecurity.GetTokens()[0])
+	}
+	if _, ok := testCaseSecurity.GetTokens()[2].(SecurityTokenReference); !ok {
+		t.Fatalf("Security.Tokens[2] = %T; want SecurityTokenReference", testCaseSecurity.GetTokens()[2])
+	}
+	if testCaseSecurity.GetTokenById("U-1") != testCaseSecurity.GetTokens()[1] {
+		t.Fatal("Security.GetTokenById(U-1) did not return the unknown token")
+	}
+	if testCaseSecurity.GetTokenById("STR-1") != testCaseSecurity.GetTokens()[2] {
+		t.Fatal("Security.GetTokenById(STR-1) did not return the SecurityTokenReference")
+	}
+	if testCaseSecurity.GetTokenById("missing") != nil {
+		t.Fatal("Security.GetTokenById(missing) = not nil; want nil")
+	}
+
+	binarySecurityToken, ok := GetSecurityToken[BinarySecurityToken](testCaseSecurity)
+	if !ok {
+		t.Fatal("GetSecurityToken[BinarySecurityToken] not found")
+	}
+	if binarySecurityToken.GetId() != "BST-1" {
+		t.Fatalf("BinarySecurityToken.Id = %s; want BST-1", binarySecurityToken.GetId())
+	}
+	if len(GetSecurityTokens[SecurityTokenReference](testCaseSecurity)) != 1 {
+		t.Fatal("len(GetSecurityTokens[SecurityTokenReference]) != 1")
+	}
+}
+
+func Test_Security_LoadXml_Soap12(t *testing.T) {
+	// Create test case XML
+	testCaseXml := fmt.Sprintf(
+		`<wsse:Security env:mustUnderstand="true" env:role="ultimate" xmlns:env="%s" xmlns:wsse="%s"/>`,
+		Soap12Namespace,
+		WsseNamespace,
+	)
+
+	// Prepare the test case
+	testCaseDocument := etree.NewDocument()
+	err := testCaseDocument.ReadFromString(testCaseXml)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCaseContext := xml.NewContext(testCaseDocument)
+	ConfigureContext(testCaseContext)
+
+	// Create test case Security
+	testCaseSecurity, err := NewSecurity(testCaseContext)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Load test case Security
+	err = testCaseSecurity.LoadXml(testCaseContext, testCaseDocument.Root())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Validate test case Security
+	if !testCaseSecurity.GetMustUnderstand() {
+		t.Fatal("Security.MustUnderstand = false; want true")
+	}
+	if testCaseSecurity.GetRole() != "ultimate" {
+		t.Fatalf("Security.Role = %s; want ultimate", testCaseSecurity.GetRole())
+	}
+	if testCaseSecurity.GetActor() != "" {
+		t.Fatalf("Security.Actor = %s; want empty", testCaseSecurity.GetActor())
+	}
+}
+
+func Test_Security_GetXml(t *testing.T) {
+	// Create test case XML
+	testCaseXml := fmt.Sprintf(
+		`<wsse:Security soap:mustUnderstand="1" xmlns:soap="%s" xmlns:wsse="%s">`+
+			`<wsse:BinarySecurityToken xmlns:wsu="%s" wsu:Id="BST-1">cert_data</wsse:BinarySecurityToken>`+
+			`<wsse:SecurityTokenReference><wsse:Reference URI="#BST-1"/></wsse:SecurityTokenReference>`+
+			`</wsse:Security>`,
+		Soap11Namespace,
+		WsseNamespace,
+		WsuNamespace,
+	)
+
+	// Prepare the test case
+	testCaseDocument := etree.NewDocument()
+	testCaseContext := xml.NewContext(testCaseDocument)
+	ConfigureContext(testCaseContext)
+
+	// Create test case Security
+	testCaseSecurity, err := NewSecurity(testCaseContext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCaseSecurity.SetMustUnderstand(true)
+
+	testCaseBinarySecurityToken, err := NewBinarySecurityToken(testCaseContext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCaseBinarySecurityToken.SetId("BST-1")
+	testCaseBinarySecurityToken.SetValue("cert_data")
+	testCaseSecurity.AddToken(testCaseBinarySecurityToken)
+
+	testCaseReference, err := NewReference(testCaseContext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCaseReference.SetUri("#BST-1")
+	testCaseSecurityTokenReference, err := NewSecurityTokenReference(testCaseContext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCaseSecurityTokenReference.SetContent(testCaseReference)
+	testCaseSecurity.AddToken(testCaseSecurityTokenReference)
+
+	// Get test case Security XML
+	testCaseSecurityElement, err := testCaseSecurity.GetXml(testCaseContext)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Add the namespace declarations
+	testCaseSecurityElement.CreateAttr("xmlns:soap", Soap11Namespace)
+	testCaseSecurityElement.CreateAttr("xmlns:wsse", WsseNamespace)
+
+	// Get the test case XML
+	testCaseDocument.SetRoot(testCaseSecurityElement)
+	resultXml, err := testCaseDocument.WriteToString()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Validate test case XML
+	if resultXml != testCaseXml {
+		t.Fatalf("Security.GetXml() = %s; want %s", resultXml, testCaseXml)
+	}
+}
+
+func Test_Security_GetXml_WsuNamespace(t *testing.T) {
+	// Prepare the test case
+	testCaseDocument := etree.NewDocument()
+	testCaseContext := xml.NewContext(testCaseDocument)
+	ConfigureContext(testCaseContext)
+
+	// Create test case Security
+	testCaseSecurity, err := NewSecurity(testCaseContext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCaseTimestamp, err := NewTimestamp(testCaseContext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCaseTimestamp.SetId("TS-1")
+	testCaseSecurity.AddToken(testCaseTimestamp)
+
+	// Get test case Security XML
+	testCaseSecurityElement, err := testCaseSecurity.GetXml(testCaseContext)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Validate the wsu namespace declaration
+	timestampEl := testCaseSecurityElement.SelectElement("Timestamp")
+	if timestampEl.SelectAttrValue("xmlns:wsu", "") != WsuNamespace {
+		t.Fatal("Security.GetXml() did not declare the wsu namespace")
+	}
+	if GetWsuId(nil, timestampEl) != "TS-1" {
+		t.Fatalf("Timestamp wsu:Id = %s; want TS-1", GetWsuId(nil, timestampEl))
+	}
+}
@@ -2,7 +2,6 @@ package xmlsecurity
 
 import (
 	"crypto/x509"
-	"errors"
 
 	"github.com/beevik/etree"
 	"github.com/deb-ict/go-xml"
@@ -11,6 +10,7 @@ import (
 type SecurityTokenReference interface {
 	xml.Node
 	X509CertificateProvider
+	X509ChainProvider
 	GetId() string
 	SetId(id string)
 	GetUsage() string
@@ -70,14 +70,46 @@ func (node *securityTokenReference) SetContent(content xml.Node) {
 
 func (node *securityTokenReference) GetX509Certificate(context xml.Context) (*x509.Certificate, error) {
 	if node.Content == nil {
-		return nil, errors.New("x509 certificate not available")
+		return nil, ErrX509CertificateNotAvailable
 	}
 	provider, ok := node.Content.(X509CertificateProvider)
 	if !ok {
-		return nil, errors.New("x509 certificate not available")
+		return nil, ErrX509CertificateNotAvailable
 	}
 
-	return provider.GetX509Certificate(context)
+	certificate, err := provider.GetX509Certificate(context)
+	if err != nil {
+		return nil, err
+	}
+	err = node.checkUsage(context, certificate)
+	if err != nil {
+		return nil, err
+	}
+	return certificate, nil
+}
+
+func (node *securityTokenReference) GetX509CertificateChain(context xml.Context) ([]*x509.Certificate, error) {
+	if node.Content == nil {
+		return nil, ErrX509CertificateNotAvailable
+	}
+
+	chain, err := getX509CertificateChain(context, node.Content)
+	if err != nil {
+		return nil, err
+	}
+	err = node.checkUsage(context, chain[0])
+	if err != nil {
+		return nil, err
+	}
+	return chain, nil
+}
+
+func (node *securityTokenReference) checkUsage(context xml.Context, certificate *x509.Certificate) error {
+	policy := GetKeyUsagePolicy(context)
+	if policy == nil {
+		return nil
+	}
+	return policy.CheckUsage(node.GetUsage(), certificate)
 }
 
 func (node *securityTokenReference) LoadXml(context xml.Context, el *etree.Element) error {
@@ -91,23 +123,13 @@ func (node *securityTokenReference) LoadXml(context xml.Context, el *etree.Eleme
 	node.SetTokenType(el.SelectAttrValue("TokenType", ""))
 
 	for _, child := range el.ChildElements() {
-		namespaceUri := context.GetNamespaceUri(child.Space)
-		typeConstructor, err := context.GetTypeConstructor(namespaceUri, child.Tag)
+		content, err := loadNode(context, child)
 		if err == xml.ErrNoTypeConstructor {
 			continue
 		}
 		if err != nil {
 			return err
 		}
-
-		content, err := typeConstructor(context)
-		if err != nil {
-			return err
-		}
-		err = content.LoadXml(context, child)
-		if err != nil {
-			return err
-		}
 		node.SetContent(content)
 	}
 
@@ -115,6 +137,11 @@ func (node *securityTokenReference) LoadXml(context xml.Context, el *etree.Eleme
 }
 
 func (node *securityTokenReference) GetXml(context xml.Context) (*etree.Element, error) {
+	err := generateMissingId(context, node, "SecurityTokenReference")
+	if err != nil {
+		return nil, err
+	}
+
 	el := etree.NewElement("SecurityTokenReference")
 	el.Space = context.GetNamespacePrefix(WsseNamespace)
 
@@ -0,0 +1,177 @@
+package xmlsecurity
+
+import (
+	"github.com/beevik/etree"
+	"github.com/deb-ict/go-xml"
+)
+
+type Signature interface {
+	xml.Node
+	GetId() string
+	SetId(id string)
+	GetSignedInfo() SignedInfo
+	SetSignedInfo(signedInfo SignedInfo)
+	GetSignatureValue() SignatureValue
+	SetSignatureValue(signatureValue SignatureValue)
+	GetKeyInfo() KeyInfo
+	SetKeyInfo(keyInfo KeyInfo)
+	GetObjects() []xml.Node
+	SetObjects(objects []xml.Node)
+}
+
+type signature struct {
+	Id             string
+	SignedInfo     SignedInfo
+	SignatureValue SignatureValue
+	KeyInfo        KeyInfo
+	Objects        []xml.Node
+}
+
+func NewSignature(context xml.Context) (Signature, error) {
+	return &signature{
+		Objects: make([]xml.Node, 0),
+	}, nil
+}
+
+func NewSignatureNode(context xml.Context) (xml.Node, error) {
+	return NewSignature(context)
+}
+
+func (node *signature) GetId() string {
+	return node.Id
+}
+
+func (node *signature) SetId(id string) {
+	node.Id = id
+}
+
+func (node *signature) GetSignedInfo() SignedInfo {
+	return node.SignedInfo
+}
+
+func (node *signature) SetSignedInfo(signedInfo SignedInfo) {
+	node.SignedInfo = signedInfo
+}
+
+func (node *signature) GetSignatureValue() SignatureValue {
+	return node.SignatureValue
+}
+
+func (node *signature) SetSignatureValue(signatureValue SignatureValue) {
+	node.SignatureValue = signatureValue
+}
+
+func (node *signature) GetKeyInfo() KeyInfo {
+	return node.KeyInfo
+}
+
+func (node *signature) SetKeyInfo(keyInfo KeyInfo) {
+	node.KeyInfo = keyInfo
+}
+
+func (node *signature) GetObjects() []xml.Node {
+	return node.Objects
+}
+
+func (node *signature) SetObjects(objects []xml.Node) {
+	node.Objects = objects
+}
+
+func (node *signature) LoadXml(context xml.Context, el *etree.Element) error {
+	err := xml.ValidateElement(el, "Signature", DsigNamespace)
+	if err != nil {
+		return err
+	}
+
+	node.SetId(el.SelectAttrValue("Id", ""))
+	node.SetSignedInfo(nil)
+	node.SetSignatureValue(nil)
+	node.SetKeyInfo(nil)
+	node.SetObjects(make([]xml.Node, 0))
+
+	// Children follow the schema sequence SignedInfo, SignatureValue, KeyInfo?, Object*
+	sequence := newElementSequence(context, el)
+	signedInfoEl := sequence.next("SignedInfo", DsigNamespace)
+	if signedInfoEl == nil {
+		return xml.ErrChildElementNotFound
+	}
+	signedInfo, err := NewSignedInfo(context)
+	if err != nil {
+		return err
+	}
+	err = signedInfo.LoadXml(context, signedInfoEl)
+	if err != nil {
+		return err
+	}
+	node.SetSignedInfo(signedInfo)
+
+	signatureValueEl := sequence.next("SignatureValue", DsigNamespace)
+	if signatureValueEl == nil {
+		return xml.ErrChildElementNotFound
+	}
+	signatureValue, err := NewSignatureValue(context)
+	if err != nil {
+		return err
+	}
+	err = signatureValue.LoadXml(context, signatureValueEl)
+	if err != nil {
+		return err
+	}
+	node.SetSignatureValue(signatureValue)
+
+	if keyInfoEl := sequence.next("KeyInfo", DsigNamespace); keyInfoEl != nil {
+		keyInfo, err := NewKeyInfo(context)
+		if err != nil {
+			return err
+		}
+		err = keyInfo.LoadXml(context, keyInfoEl)
+		if err != nil {
+			return err
+		}
+		node.SetKeyInfo(keyInfo)
+	}
+
+	for objectEl := sequence.next("Object", DsigNamespace); objectEl != nil; objectEl = sequence.next("Object", DsigNamespace) {
+		object := &rawNode{}
+		err := object.LoadXml(context, objectEl)
+		if err != nil {
+			return err
+		}
+		node.Objects = append(node.Objects, object)
+	}
+	if !sequence.done() {
+		return ErrUnexpectedChildElement
+	}
+
+	return nil
+}
+
+func (node *signature) GetXml(context xml.Context) (*etree.Element, error) {
+	el := etree.NewElement("Signature")
+	el.Space = context.GetNamespacePrefix(DsigNamespace)
+
+	if node.GetId() != "" {
+		el.CreateAttr("Id", node.GetId())
+	}
+
+	children := []xml.Node{}
+	if node.GetSignedInfo() != nil {
+		children = append(children, node.GetSignedInfo())
+	}
+	if node.GetSignatureValue() != nil {
+		children = append(children, node.GetSignatureValue())
+	}
+	if node.GetKeyInfo() != nil {
+		children = append(children, node.GetKeyInfo())
+	}
+	children = append(children, node.GetObjects()...)
+	for _, child := range children {
+		childEl, err := child.GetXml(context)
+		if err != nil {
+			return nil, err
+		}
+		el.AddChild(childEl)
+	}
+
+	return el, nil
+}
@@ -0,0 +1,189 @@
+package xmlsecurity
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/asn1"
+	"errors"
+	"math/big"
+
+	"github.com/beevik/etree"
+	"github.com/deb-ict/go-xml"
+)
+
+const (
+	RSASHA1SignatureAlgorithm     string = "http://www.w3.org/2000/09/xmldsig#rsa-sha1"
+	RSASHA256SignatureAlgorithm   string = "http://www.w3.org/2001/04/xmldsig-more#rsa-sha256"
+	RSASHA384SignatureAlgorithm   string = "http://www.w3.org/2001/04/xmldsig-more#rsa-sha384"
+	RSASHA512SignatureAlgorithm   string = "http://www.w3.org/2001/04/xmldsig-more#rsa-sha512"
+	ECDSASHA1SignatureAlgorithm   string = "http://www.w3.org/2001/04/xmldsig-more#ecdsa-sha1"
+	ECDSASHA256SignatureAlgorithm string = "http://www.w3.org/2001/04/xmldsig-more#ecdsa-sha256"
+	ECDSASHA384SignatureAlgorithm string = "http://www.w3.org/2001/04/xmldsig-more#ecdsa-sha384"
+	ECDSASHA512SignatureAlgorithm string = "http://www.w3.org/2001/04/xmldsig-more#ecdsa-sha512"
+)
+
+var (
+	ErrUnsupportedSignatureAlgorithm = errors.New("unsupported signature algorithm")
+	ErrSignatureAlgorithmKeyMismatch = errors.New("signature algorithm does not match the key type")
+	ErrSignatureValueInvalid         = errors.New("signature value is invalid")
+)
+
+type SignatureMethod interface {
+	xml.Node
+	GetAlgorithm() string
+	SetAlgorithm(algorithm string)
+}
+
+type signatureMethod struct {
+	Algorithm string
+}
+
+func NewSignatureMethod(context xml.Context) (SignatureMethod, error) {
+	return &signatureMethod{}, nil
+}
+
+func NewSignatureMethodNode(context xml.Context) (xml.Node, error) {
+	return NewSignatureMethod(context)
+}
+
+func (node *signatureMethod) GetAlgorithm() string {
+	return node.Algorithm
+}
+
+func (node *signatureMethod) SetAlgorithm(algorithm string) {
+	node.Algorithm = algorithm
+}
+
+func (node *signatureMethod) LoadXml(context xml.Context, el *etree.Element) error {
+	err := xml.ValidateElement(el, "SignatureMethod", DsigNamespace)
+	if err != nil {
+		return err
+	}
+
+	node.SetAlgorithm(el.SelectAttrValue("Algorithm", ""))
+
+	return nil
+}
+
+func (node *signatureMethod) GetXml(context xml.Context) (*etree.Element, error) {
+	el := etree.NewElement("SignatureMethod")
+	el.Space = context.GetNamespacePrefix(DsigNamespace)
+
+	el.CreateAttr("Algorithm", node.GetAlgorithm())
+
+	return el, nil
+}
+
+func getSignatureHash(algorithm string) (crypto.Hash, error) {
+	switch algorithm {
+	case RSASHA1SignatureAlgorithm, ECDSASHA1SignatureAlgorithm:
+		return crypto.SHA1, nil
+	case RSASHA256SignatureAlgorithm, ECDSASHA256SignatureAlgorithm:
+		return crypto.SHA256, nil
+	case RSASHA384SignatureAlgorithm, ECDSASHA384SignatureAlgorithm:
+		return crypto.SHA384, nil
+	case RSASHA512SignatureAlgorithm, ECDSASHA512SignatureAlgorithm:
+		return crypto.SHA512, nil
+	}
+	return 0, ErrUnsupportedSignatureAlgorithm
+}
+
+func isECDSASignatureAlgorithm(algorithm string) bool {
+	switch algorithm {
+	case ECDSASHA1SignatureAlgorithm, ECDSASHA256SignatureAlgorithm, ECDSASHA384SignatureAlgorithm, ECDSASHA512SignatureAlgorithm:
+		return true
+	}
+	return false
+}
+
+func getDefaultSignatureAlgorithm(publicKey crypto.PublicKey) (string, error) {
+	switch publicKey.(type) {
+	case *rsa.PublicKey:
+		return RSASHA256SignatureAlgorithm, nil
+	case *ecdsa.PublicKey:
+		return ECDSASHA256SignatureAlgorithm, nil
+	}
+	return "", ErrUnsupportedSignatureAlgorithm
+}
+
+func checkSignatureAlgorithmKey(algorithm string, publicKey crypto.PublicKey) error {
+	_, err := getSignatureHash(algorithm)
+	if err != nil {
+		return err
+	}
+
+	switch publicKey.(type) {
+	case *rsa.PublicKey:
+		if !isECDSASignatureAlgorithm(algorithm) {
+			return nil
+		}
+	case *ecdsa.PublicKey:
+		if isECDSASignatureAlgorithm(algorithm) {
+			return nil
+		}
+	}
+	return ErrSignatureAlgorithmKeyMismatch
+}
+
+func createSignatureValue(signer crypto.Signer, algorithm string, data []byte) ([]byte, error) {
+	err := checkSignatureAlgorithmKey(algorithm, signer.Public())
+	if err != nil {
+		return nil, err
+	}
+	hash, _ := getSignatureHash(algorithm)
+	h := hash.New()
+	h.Write(data)
+
+	value, err := signer.Sign(rand.Reader, h.Sum(nil), hash)
+	if err != nil {
+		return nil, err
+	}
+	if !isECDSASignatureAlgorithm(algorithm) {
+		return value, nil
+	}
+
+	// XML-DSig encodes ECDSA signatures as the concatenation of r and s
+	var ecdsaValue struct {
+		R, S *big.Int
+	}
+	_, err = asn1.Unmarshal(value, &ecdsaValue)
+	if err != nil {
+		return nil, err
+	}
+	size := (signer.Public().(*ecdsa.PublicKey).Curve.Params().BitSize + 7) / 8
+	result := make([]byte, 2*size)
+	ecdsaValue.R.FillBytes(result[:size])
+	ecdsaValue.S.FillBytes(result[size:])
+	return result, nil
+}
+
+func verifySignatureValue(publicKey crypto.PublicKey, algorithm string, data []byte, value []byte) error {
+	err := checkSignatureAlgorithmKey(algorithm, publicKey)
+	if err != nil {
+		return err
+	}
+	hash, _ := getSignatureHash(algorithm)
+	h := hash.New()
+	h.Write(data)
+	digest := h.Sum(nil)
+
+	switch publicKey := publicKey.(type) {
+	case *rsa.PublicKey:
+		if rsa.VerifyPKCS1v15(publicKey, hash, digest, value) != nil {
+			return ErrSignatureValueInvalid
+		}
+	case *ecdsa.PublicKey:
+		size := (publicKey.Curve.Params().BitSize + 7) / 8
+		if len(value) != 2*size {
+			return ErrSignatureValueInvalid
+		}
+		r := new(big.Int).SetBytes(value[:size])
+		s := new(big.Int).SetBytes(value[size:])
+		if !ecdsa.Verify(publicKey, digest, r, s) {
+			return ErrSignatureValueInvalid
+		}
+	}
+	return nil
+}
@@ -0,0 +1,247 @@
+package xmlsecurity
+
+import (
+	"encoding/base64"
+	"errors"
+
+	"github.com/beevik/etree"
+	"github.com/deb-ict/go-xml"
+)
+
+var (
+	ErrUnsupportedTransform = errors.New("unsupported transform")
+)
+
+type SignatureReference interface {
+	xml.Node
+	GetId() string
+	SetId(id string)
+	GetUri() string
+	SetUri(uri string)
+	GetType() string
+	SetType(referenceType string)
+	GetTransforms() []Transform
+	SetTransforms(transforms []Transform)
+	AddTransform(transform Transform)
+	GetDigestMethod() DigestMethod
+	SetDigestMethod(digestMethod DigestMethod)
+	GetDigestValue() []byte
+	SetDigestValue(digestValue []byte)
+}
+
+type signatureReference struct {
+	Id           string
+	Uri          string
+	Type         string
+	Transforms   []Transform
+	DigestMethod DigestMethod
+	DigestValue  []byte
+}
+
+func NewSignatureReference(context xml.Context) (SignatureReference, error) {
+	return &signatureReference{
+		Transforms: make([]Transform, 0),
+	}, nil
+}
+
+func NewSignatureReferenceNode(context xml.Context) (xml.Node, error) {
+	return NewSignatureReference(context)
+}
+
+func (node *signatureReference) GetId() string {
+	return node.Id
+}
+
+func (node *signatureReference) SetId(id string) {
+	node.Id = id
+}
+
+func (node *signatureReference) GetUri() string {
+	return node.Uri
+}
+
+func (node *signatureReference) SetUri(uri string) {
+	node.Uri = uri
+}
+
+func (node *signatureReference) GetType() string {
+	return node.Type
+}
+
+func (node *signatureReference) SetType(referenceType string) {
+	node.Type = referenceType
+}
+
+func (node *signatureReference) GetTransforms() []Transform {
+	return node.Transforms
+}
+
+func (node *signatureReference) SetTransforms(transforms []Transform) {
+	node.Transforms = transforms
+}
+
+func (node *signatureReference) AddTransform(transform Transform) {
+	node.Transforms = append(node.Transforms, transform)
+}
+
+func (node *signatureReference) GetDigestMethod() DigestMethod {
+	return node.DigestMethod
+}
+
+func (node *signatureReference) SetDigestMethod(digestMethod DigestMethod) {
+	node.DigestMethod = digestMethod
+}
+
+func (node *signatureReference) GetDigestValue() []byte {
+	return node.DigestValue
+}
+
+func (node *signatureReference) SetDigestValue(digestValue []byte) {
+	node.DigestValue = digestValue
+}
+
+func (node *signatureReference) LoadXml(context xml.Context, el *etree.Element) error {
+	err := xml.ValidateElement(el, "Reference", DsigNamespace)
+	if err != nil {
+		return err
+	}
+
+	node.SetId(el.SelectAttrValue("Id", ""))
+	node.SetUri(el.SelectAttrValue("URI", ""))
+	node.SetType(el.SelectAttrValue("Type", ""))
+	node.SetTransforms(make([]Transform, 0))
+	node.SetDigestMethod(nil)
+	node.SetDigestValue(nil)
+
+	// Children follow the schema sequence Transforms?, DigestMethod, DigestValue
+	sequence := newElementSequence(context, el)
+	if transformsEl := sequence.next("Transforms", DsigNamespace); transformsEl != nil {
+		transforms := newElementSequence(context, transformsEl)
+		for transformEl := transforms.next("Transform", DsigNamespace); transformEl != nil; transformEl = transforms.next("Transform", DsigNamespace) {
+			transform, err := NewTransform(context)
+			if err != nil {
+				return err
+			}
+			err = transform.LoadXml(context, transformEl)
+			if err != nil {
+				return err
+			}
+			node.AddTransform(transform)
+		}
+		if len(node.GetTransforms()) == 0 {
+			return xml.ErrChildElementNotFound
+		}
+		if !transforms.done() {
+			return ErrUnexpectedChildElement
+		}
+	}
+
+	digestMethodEl := sequence.next("DigestMethod", DsigNamespace)
+	if digestMethodEl == nil {
+		return xml.ErrChildElementNotFound
+	}
+	digestMethod, err := NewDigestMethod(context)
+	if err != nil {
+		return err
+	}
+	err = digestMethod.LoadXml(context, digestMethodEl)
+	if err != nil {
+		return err
+	}
+	node.SetDigestMethod(digestMethod)
+
+	digestValueEl := sequence.next("DigestValue", DsigNamespace)
+	if digestValueEl == nil {
+		return xml.ErrChildElementNotFound
+	}
+	digestValue, err := decodeBase64Text(digestValueEl.Text())
+	if err != nil {
+		return err
+	}
+	node.SetDigestValue(digestValue)
+	if !sequence.done() {
+		return ErrUnexpectedChildElement
+	}
+
+	return nil
+}
+
+func (node *signatureReference) GetXml(context xml.Context) (*etree.Element, error) {
+	el := etree.NewElement("Reference")
+	el.Space = context.GetNamespacePrefix(DsigNamespace)
+
+	if node.GetId() != "" {
+		el.CreateAttr("Id", node.GetId())
+	}
+	el.CreateAttr("URI", node.GetUri())
+	if node.GetType() != "" {
+		el.CreateAttr("Type", node.GetType())
+	}
+
+	if len(node.GetTransforms()) > 0 {
+		transformsEl := el.CreateElement("Transforms")
+		transformsEl.Space = context.GetNamespacePrefix(DsigNamespace)
+		for _, transform := range node.GetTransforms() {
+			transformEl, err := transform.GetXml(context)
+			if err != nil {
+				return nil, err
+			}
+			transformsEl.AddChild(transformEl)
+		}
+	}
+	if node.GetDigestMethod() != nil {
+		digestMethodEl, err := node.GetDigestMethod().GetXml(context)
+		if err != nil {
+			return nil, err
+		}
+		el.AddChild(digestMethodEl)
+	}
+	digestValueEl := el.CreateElement("DigestValue")
+	digestValueEl.Space = context.GetNamespacePrefix(DsigNamespace)
+	digestValueEl.SetText(base64.StdEncoding.EncodeToString(node.GetDigestValue()))
+
+	return el, nil
+}
+
+func digestReference(reference SignatureReference, el *etree.Element, signatureEl *etree.Element) ([]byte, error) {
+	if reference.GetDigestMethod() == nil {
+		return nil, ErrUnsupportedDigestAlgorithm
+	}
+	hash, err := getDigestHash(reference.GetDigestMethod().GetAlgorithm())
+	if err != nil {
+		return nil, err
+	}
+
+	enveloped := false
+	var canonicalizer Canonicalizer
+	for _, transform := range reference.GetTransforms() {
+		if transform.GetAlgorithm() == EnvelopedSignatureAlgorithm {
+			enveloped = true
+			continue
+		}
+		canonicalizer, err = transform.GetCanonicalizer()
+		if err != nil {
+			return nil, ErrUnsupportedTransform
+		}
+	}
+	// A node-set is converted to octets with inclusive C14N when no transform says otherwise
+	if canonicalizer == nil {
+		canonicalizer = NewC14N10Canonicalizer(false)
+	}
+
+	// The enveloped signature is detached while the digest is computed
+	if enveloped && signatureEl != nil && containsElement(el, signatureEl) {
+		parent := signatureEl.Parent()
+		index := signatureEl.Index()
+		parent.RemoveChildAt(index)
+		defer parent.InsertChildAt(index, signatureEl)
+	}
+
+	data, err := canonicalizer.Canonicalize(el)
+	if err != nil {
+		return nil, err
+	}
+	h := hash.New()
+	h.Write(data)
+	return h.Sum(nil), nil
+}
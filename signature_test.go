@@ -0,0 +1,305 @@
+package xmlsecurity
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/beevik/etree"
+	"github.com/deb-ict/go-xml"
+)
+
+func Test_Signature_RoundTrip(t *testing.T) {
+	// Create test case XML
+	testCaseXml := fmt.Sprintf(
+		`<wsse:Security xmlns:ds="%s" xmlns:ec="%s" xmlns:wsse="%s">`+
+			`<wsse:BinarySecurityToken xmlns:wsu="%s" wsu:Id="BST-1">cert_data</wsse:BinarySecurityToken>`+
+			`<ds:Signature Id="SIG-1">`+
+			`<ds:SignedInfo>`+
+			`<ds:CanonicalizationMethod Algorithm="%s"><ec:InclusiveNamespaces PrefixList="wsse soap"/></ds:CanonicalizationMethod>`+
+			`<ds:SignatureMethod Algorithm="%s"/>`+
+			`<ds:Reference URI="#TS-1">`+
+			`<ds:Transforms><ds:Transform Algorithm="%s"><ec:InclusiveNamespaces PrefixList="wsu"/></ds:Transform></ds:Transforms>`+
+			`<ds:DigestMethod Algorithm="%s"/>`+
+			`<ds:DigestValue>AQID</ds:DigestValue>`+
+			`</ds:Reference>`+
+			`</ds:SignedInfo>`+
+			`<ds:SignatureValue>BAUG</ds:SignatureValue>`+
+			`<ds:KeyInfo Id="KI-1">`+
+			`<wsse:SecurityTokenReference xmlns:wsu="%s" wsu:Id="STR-1"><wsse:Reference URI="#BST-1"/></wsse:SecurityTokenReference>`+
+			`</ds:KeyInfo>`+
+			`</ds:Signature>`+
+			`</wsse:Security>`,
+		DsigNamespace,
+		ExcC14NNamespace,
+		WsseNamespace,
+		WsuNamespace,
+		ExcC14NAlgorithm,
+		RSASHA256SignatureAlgorithm,
+		ExcC14NAlgorithm,
+		SHA256DigestAlgorithm,
+		WsuNamespace,
+	)
+
+	// Prepare the test case
+	testCaseDocument := etree.NewDocument()
+	err := testCaseDocument.ReadFromString(testCaseXml)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCaseContext := xml.NewContext(testCaseDocument)
+	ConfigureContext(testCaseContext)
+
+	// Load test case Security
+	testCaseSecurity, err := NewSecurity(testCaseContext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = testCaseSecurity.LoadXml(testCaseContext, testCaseDocument.Root())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Validate test case Signature
+	testCaseSignature, ok := GetSecurityToken[Signature](testCaseSecurity)
+	if !ok {
+		t.Fatal("GetSecurityToken[Signature] not found")
+	}
+	if testCaseSignature.GetId() != "SIG-1" {
+		t.Fatalf("Signature.Id = %s; want SIG-1", testCaseSignature.GetId())
+	}
+	signedInfo := testCaseSignature.GetSignedInfo()
+	if signedInfo.GetCanonicalizationMethod().GetAlgorithm() != ExcC14NAlgorithm {
+		t.Fatalf("CanonicalizationMethod.Algorithm = %s; want %s", signedInfo.GetCanonicalizationMethod().GetAlgorithm(), ExcC14NAlgorithm)
+	}
+	if fmt.Sprint(signedInfo.GetCanonicalizationMethod().GetInclusiveNamespaces()) != "[wsse soap]" {
+		t.Fatalf("CanonicalizationMethod.InclusiveNamespaces = %v; want [wsse soap]", signedInfo.GetCanonicalizationMethod().GetInclusiveNamespaces())
+	}
+	if signedInfo.GetSignatureMethod().GetAlgorithm() != RSASHA256SignatureAlgorithm {
+		t.Fatalf("SignatureMethod.Algorithm = %s; want %s", signedInfo.GetSignatureMethod().GetAlgorithm(), RSASHA256SignatureAlgorithm)
+	}
+	if len(signedInfo.GetReferences()) != 1 {
+		t.Fatalf("len(SignedInfo.References) = %d; want 1", len(signedInfo.GetReferences()))
+	}
+	reference := signedInfo.GetReferences()[0]
+	if reference.GetUri() != "#TS-1" {
+		t.Fatalf("SignatureReference.Uri = %s; want #TS-1", reference.GetUri())
+	}
+	if len(reference.GetTransforms()) != 1 || reference.GetTransforms()[0].GetInclusiveNamespaces()[0] != "wsu" {
+		t.Fatal("SignatureReference.Transforms did not load the InclusiveNamespaces")
+	}
+	if reference.GetDigestMethod().GetAlgorithm() != SHA256DigestAlgorithm {
+		t.Fatalf("DigestMethod.Algorithm = %s; want %s", reference.GetDigestMethod().GetAlgorithm(), SHA256DigestAlgorithm)
+	}
+	if !bytes.Equal(reference.GetDigestValue(), []byte{1, 2, 3}) {
+		t.Fatalf("SignatureReference.DigestValue = %v; want [1 2 3]", reference.GetDigestValue())
+	}
+	if !bytes.Equal(testCaseSignature.GetSignatureValue().GetValue(), []byte{4, 5, 6}) {
+		t.Fatalf("SignatureValue.Value = %v; want [4 5 6]", testCaseSignature.GetSignatureValue().GetValue())
+	}
+	if _, ok := testCaseSignature.GetKeyInfo().GetContent()[0].(SecurityTokenReference); !ok {
+		t.Fatalf("KeyInfo.Content[0] = %T; want SecurityTokenReference", testCaseSignature.GetKeyInfo().GetContent()[0])
+	}
+
+	// Get test case Security XML
+	testCaseSecurityElement, err := testCaseSecurity.GetXml(testCaseContext)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Add the namespace declarations
+	testCaseSecurityElement.CreateAttr("xmlns:ds", DsigNamespace)
+	testCaseSecurityElement.CreateAttr("xmlns:ec", ExcC14NNamespace)
+	testCaseSecurityElement.CreateAttr("xmlns:wsse", WsseNamespace)
+
+	// Get the test case XML
+	resultDocument := etree.NewDocument()
+	resultDocument.SetRoot(testCaseSecurityElement)
+	resultXml, err := resultDocument.WriteToString()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Validate test case XML
+	if resultXml != testCaseXml {
+		t.Fatalf("Security.GetXml() = %s; want %s", resultXml, testCaseXml)
+	}
+}
+
+func Test_Signature_LoadXml_DefaultNamespace(t *testing.T) {
+	// Create test case XML
+	testCaseXml := fmt.Sprintf(
+		`<Signature xmlns="%s">`+
+			`<SignedInfo>`+
+			`<CanonicalizationMethod Algorithm="%s"/>`+
+			`<SignatureMethod Algorithm="%s"/>`+
+			`<Reference URI="#Body-1"><DigestMethod Algorithm="%s"/><DigestValue>AQID</DigestValue></Reference>`+
+			`</SignedInfo>`+
+			"<SignatureValue>\n  BAUG\n</SignatureValue>"+
+			`<Object Id="O-1"/>`+
+			`</Signature>`,
+		DsigNamespace,
+		C14N10Algorithm,
+		ECDSASHA256SignatureAlgorithm,
+		SHA1DigestAlgorithm,
+	)
+
+	// Prepare the test case
+	testCaseDocument := etree.NewDocument()
+	err := testCaseDocument.ReadFromString(testCaseXml)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCaseContext := xml.NewContext(testCaseDocument)
+	ConfigureContext(testCaseContext)
+
+	// Load test case Signature
+	testCaseSignature, err := NewSignature(testCaseContext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = testCaseSignature.LoadXml(testCaseContext, testCaseDocument.Root())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Validate test case Signature
+	if testCaseSignature.GetSignedInfo().GetReferences()[0].GetUri() != "#Body-1" {
+		t.Fatalf("SignatureReference.Uri = %s; want #Body-1", testCaseSignature.GetSignedInfo().GetReferences()[0].GetUri())
+	}
+	if !bytes.Equal(testCaseSignature.GetSignatureValue().GetValue(), []byte{4, 5, 6}) {
+		t.Fatalf("SignatureValue.Value = %v; want [4 5 6]", testCaseSignature.GetSignatureValue().GetValue())
+	}
+	if testCaseSignature.GetKeyInfo() != nil {
+		t.Fatal("Signature.KeyInfo = not nil; want nil")
+	}
+	if len(testCaseSignature.GetObjects()) != 1 {
+		t.Fatalf("len(Signature.Objects) = %d; want 1", len(testCaseSignature.GetObjects()))
+	}
+}
+
+func Test_Signature_LoadXml_MissingSignedInfo(t *testing.T) {
+	// Prepare the test case
+	testCaseDocument := etree.NewDocument()
+	err := testCaseDocument.ReadFromString(`<ds:Signature xmlns:ds="` + DsigNamespace + `"><ds:SignatureValue>BAUG</ds:SignatureValue></ds:Signature>`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCaseContext := xml.NewContext(testCaseDocument)
+	ConfigureContext(testCaseContext)
+
+	// Load test case Signature
+	testCaseSignature, err := NewSignature(testCaseContext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = testCaseSignature.LoadXml(testCaseContext, testCaseDocument.Root())
+	if err != xml.ErrChildElementNotFound {
+		t.Fatalf("Signature.LoadXml() error = %v; want %v", err, xml.ErrChildElementNotFound)
+	}
+}
+
+func Test_Signature_LoadXml_Sequence(t *testing.T) {
+	signedInfo := fmt.Sprintf(
+		`<ds:SignedInfo>`+
+			`<ds:CanonicalizationMethod Algorithm="%s"/>`+
+			`<ds:SignatureMethod Algorithm="%s"/>`+
+			`<ds:Reference URI="#Body-1"><ds:DigestMethod Algorithm="%s"/><ds:DigestValue>AQID</ds:DigestValue></ds:Reference>`+
+			`</ds:SignedInfo>`,
+		ExcC14NAlgorithm,
+		ECDSASHA256SignatureAlgorithm,
+		SHA256DigestAlgorithm,
+	)
+	signatureValue := `<ds:SignatureValue>BAUG</ds:SignatureValue>`
+	keyInfo := `<ds:KeyInfo/>`
+
+	// Create test cases
+	testCases := []struct {
+		name    string
+		content string
+		err     error
+	}{
+		{
+			name:    "duplicate SignedInfo",
+			content: signedInfo + signedInfo + signatureValue,
+			err:     xml.ErrChildElementNotFound,
+		},
+		{
+			name:    "trailing SignedInfo",
+			content: signedInfo + signatureValue + signedInfo,
+			err:     ErrUnexpectedChildElement,
+		},
+		{
+			name:    "duplicate SignatureValue",
+			content: signedInfo + signatureValue + signatureValue,
+			err:     ErrUnexpectedChildElement,
+		},
+		{
+			name:    "duplicate KeyInfo",
+			content: signedInfo + signatureValue + keyInfo + keyInfo,
+			err:     ErrUnexpectedChildElement,
+		},
+		{
+			name:    "out of order",
+			content: signatureValue + signedInfo,
+			err:     xml.ErrChildElementNotFound,
+		},
+		{
+			name:    "KeyInfo after Object",
+			content: signedInfo + signatureValue + `<ds:Object/>` + keyInfo,
+			err:     ErrUnexpectedChildElement,
+		},
+		{
+			name:    "unexpected child",
+			content: signedInfo + signatureValue + `<Unknown/>`,
+			err:     ErrUnexpectedChildElement,
+		},
+		{
+			name:    "missing SignatureValue",
+			content: signedInfo + keyInfo,
+			err:     xml.ErrChildElementNotFound,
+		},
+		{
+			name:    "duplicate CanonicalizationMethod",
+			content: strings.Replace(signedInfo, `<ds:SignatureMethod`, fmt.Sprintf(`<ds:CanonicalizationMethod Algorithm="%s"/><ds:SignatureMethod`, C14N10Algorithm), 1) + signatureValue,
+			err:     xml.ErrChildElementNotFound,
+		},
+		{
+			name:    "duplicate SignatureMethod",
+			content: strings.Replace(signedInfo, `<ds:Reference`, fmt.Sprintf(`<ds:SignatureMethod Algorithm="%s"/><ds:Reference`, RSASHA256SignatureAlgorithm), 1) + signatureValue,
+			err:     xml.ErrChildElementNotFound,
+		},
+		{
+			name:    "SignatureMethod after Reference",
+			content: strings.Replace(signedInfo, `</ds:SignedInfo>`, fmt.Sprintf(`<ds:SignatureMethod Algorithm="%s"/></ds:SignedInfo>`, RSASHA256SignatureAlgorithm), 1) + signatureValue,
+			err:     ErrUnexpectedChildElement,
+		},
+		{
+			name:    "duplicate DigestValue",
+			content: strings.Replace(signedInfo, `</ds:Reference>`, `<ds:DigestValue>BAUG</ds:DigestValue></ds:Reference>`, 1) + signatureValue,
+			err:     ErrUnexpectedChildElement,
+		},
+	}
+
+	for _, tc := range testCases {
+		// Prepare the test case
+		testCaseDocument := etree.NewDocument()
+		err := testCaseDocument.ReadFromString(`<ds:Signature xmlns:ds="` + DsigNamespace + `">` + tc.content + `</ds:Signature>`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		testCaseContext := xml.NewContext(testCaseDocument)
+		ConfigureContext(testCaseContext)
+
+		// Load test case Signature
+		testCaseSignature, err := NewSignature(testCaseContext)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = testCaseSignature.LoadXml(testCaseContext, testCaseDocument.Root())
+		if err != tc.err {
+			t.Fatalf("Signature.LoadXml(%s) error = %v; want %v", tc.name, err, tc.err)
+		}
+	}
+}
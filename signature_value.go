@@ -0,0 +1,73 @@
+package xmlsecurity
+
+import (
+	"encoding/base64"
+
+	"github.com/beevik/etree"
+	"github.com/deb-ict/go-xml"
+)
+
+type SignatureValue interface {
+	xml.Node
+	GetId() string
+	SetId(id string)
+	GetValue() []byte
+	SetValue(value []byte)
+}
+
+type signatureValue struct {
+	Id    string
+	Value []byte
+}
+
+func NewSignatureValue(context xml.Context) (SignatureValue, error) {
+	return &signatureValue{}, nil
+}
+
+func NewSignatureValueNode(context xml.Context) (xml.Node, error) {
+	return NewSignatureValue(context)
+}
+
+func (node *signatureValue) GetId() string {
+	return node.Id
+}
+
+func (node *signatureValue) SetId(id string) {
+	node.Id = id
+}
+
+func (node *signatureValue) GetValue() []byte {
+	return node.Value
+}
+
+func (node *signatureValue) SetValue(value []byte) {
+	node.Value = value
+}
+
+func (node *signatureValue) LoadXml(context xml.Context, el *etree.Element) error {
+	err := xml.ValidateElement(el, "SignatureValue", DsigNamespace)
+	if err != nil {
+		return err
+	}
+
+	value, err := decodeBase64Text(el.Text())
+	if err != nil {
+		return err
+	}
+	node.SetId(el.SelectAttrValue("Id", ""))
+	node.SetValue(value)
+
+	return nil
+}
+
+func (node *signatureValue) GetXml(context xml.Context) (*etree.Element, error) {
+	el := etree.NewElement("SignatureValue")
+	el.Space = context.GetNamespacePrefix(DsigNamespace)
+
+	if node.GetId() != "" {
+		el.CreateAttr("Id", node.GetId())
+	}
+	el.SetText(base64.StdEncoding.EncodeToString(node.GetValue()))
+
+	return el, nil
+}
@@ -0,0 +1,167 @@
+package xmlsecurity
+
+import (
+	"github.com/beevik/etree"
+	"github.com/deb-ict/go-xml"
+)
+
+type SignedInfo interface {
+	xml.Node
+	GetId() string
+	SetId(id string)
+	GetCanonicalizationMethod() CanonicalizationMethod
+	SetCanonicalizationMethod(canonicalizationMethod CanonicalizationMethod)
+	GetSignatureMethod() SignatureMethod
+	SetSignatureMethod(signatureMethod SignatureMethod)
+	GetReferences() []SignatureReference
+	SetReferences(references []SignatureReference)
+	AddReference(reference SignatureReference)
+}
+
+type signedInfo struct {
+	Id                     string
+	CanonicalizationMethod CanonicalizationMethod
+	SignatureMethod        SignatureMethod
+	References             []SignatureReference
+}
+
+func NewSignedInfo(context xml.Context) (SignedInfo, error) {
+	return &signedInfo{
+		References: make([]SignatureReference, 0),
+	}, nil
+}
+
+func NewSignedInfoNode(context xml.Context) (xml.Node, error) {
+	return NewSignedInfo(context)
+}
+
+func (node *signedInfo) GetId() string {
+	return node.Id
+}
+
+func (node *signedInfo) SetId(id string) {
+	node.Id = id
+}
+
+func (node *signedInfo) GetCanonicalizationMethod() CanonicalizationMethod {
+	return node.CanonicalizationMethod
+}
+
+func (node *signedInfo) SetCanonicalizationMethod(canonicalizationMethod CanonicalizationMethod) {
+	node.CanonicalizationMethod = canonicalizationMethod
+}
+
+func (node *signedInfo) GetSignatureMethod() SignatureMethod {
+	return node.SignatureMethod
+}
+
+func (node *signedInfo) SetSignatureMethod(signatureMethod SignatureMethod) {
+	node.SignatureMethod = signatureMethod
+}
+
+func (node *signedInfo) GetReferences() []SignatureReference {
+	return node.References
+}
+
+func (node *signedInfo) SetReferences(references []SignatureReference) {
+	node.References = references
+}
+
+func (node *signedInfo) AddReference(reference SignatureReference) {
+	node.References = append(node.References, reference)
+}
+
+func (node *signedInfo) LoadXml(context xml.Context, el *etree.Element) error {
+	err := xml.ValidateElement(el, "SignedInfo", DsigNamespace)
+	if err != nil {
+		return err
+	}
+
+	node.SetId(el.SelectAttrValue("Id", ""))
+	node.SetCanonicalizationMethod(nil)
+	node.SetSignatureMethod(nil)
+	node.SetReferences(make([]SignatureReference, 0))
+
+	// Children follow the schema sequence CanonicalizationMethod, SignatureMethod, Reference+
+	sequence := newElementSequence(context, el)
+	canonicalizationMethodEl := sequence.next("CanonicalizationMethod", DsigNamespace)
+	if canonicalizationMethodEl == nil {
+		return xml.ErrChildElementNotFound
+	}
+	canonicalizationMethod, err := NewCanonicalizationMethod(context)
+	if err != nil {
+		return err
+	}
+	err = canonicalizationMethod.LoadXml(context, canonicalizationMethodEl)
+	if err != nil {
+		return err
+	}
+	node.SetCanonicalizationMethod(canonicalizationMethod)
+
+	signatureMethodEl := sequence.next("SignatureMethod", DsigNamespace)
+	if signatureMethodEl == nil {
+		return xml.ErrChildElementNotFound
+	}
+	signatureMethod, err := NewSignatureMethod(context)
+	if err != nil {
+		return err
+	}
+	err = signatureMethod.LoadXml(context, signatureMethodEl)
+	if err != nil {
+		return err
+	}
+	node.SetSignatureMethod(signatureMethod)
+
+	for referenceEl := sequence.next("Reference", DsigNamespace); referenceEl != nil; referenceEl = sequence.next("Reference", DsigNamespace) {
+		reference, err := NewSignatureReference(context)
+		if err != nil {
+			return err
+		}
+		err = reference.LoadXml(context, referenceEl)
+		if err != nil {
+			return err
+		}
+		node.AddReference(reference)
+	}
+	if len(node.GetReferences()) == 0 {
+		return xml.ErrChildElementNotFound
+	}
+	if !sequence.done() {
+		return ErrUnexpectedChildElement
+	}
+
+	return nil
+}
+
+func (node *signedInfo) GetXml(context xml.Context) (*etree.Element, error) {
+	el := etree.NewElement("SignedInfo")
+	el.Space = context.GetNamespacePrefix(DsigNamespace)
+
+	if node.GetId() != "" {
+		el.CreateAttr("Id", node.GetId())
+	}
+
+	if node.GetCanonicalizationMethod() != nil {
+		canonicalizationMethodEl, err := node.GetCanonicalizationMethod().GetXml(context)
+		if err != nil {
+			return nil, err
+		}
+		el.AddChild(canonicalizationMethodEl)
+	}
+	if node.GetSignatureMethod() != nil {
+		signatureMethodEl, err := node.GetSignatureMethod().GetXml(context)
+		if err != nil {
+			return nil, err
+		}
+		el.AddChild(signatureMethodEl)
+	}
+	for _, reference := range node.GetReferences() {
+		referenceEl, err := reference.GetXml(context)
+		if err != nil {
+			return nil, err
+		}
+		el.AddChild(referenceEl)
+	}
+
+	return el, nil
+}
@@ -0,0 +1,158 @@
+package xmlsecurity
+
+import (
+	"errors"
+	"time"
+
+	"github.com/beevik/etree"
+	"github.com/deb-ict/go-xml"
+)
+
+var (
+	ErrTimestampNotYetValid = errors.New("timestamp not yet valid")
+	ErrTimestampExpired     = errors.New("timestamp expired")
+	ErrTimestampInvalid     = errors.New("timestamp expires before it is created")
+)
+
+type Timestamp interface {
+	xml.Node
+	GetId() string
+	SetId(id string)
+	GetCreated() time.Time
+	SetCreated(created time.Time)
+	GetExpires() time.Time
+	SetExpires(expires time.Time)
+	Validate(clock Clock, skew time.Duration) error
+}
+
+type timestamp struct {
+	Id      string
+	Created time.Time
+	Expires time.Time
+}
+
+func NewTimestamp(context xml.Context) (Timestamp, error) {
+	return &timestamp{}, nil
+}
+
+func NewTimestampWithTtl(context xml.Context, clock Clock, ttl time.Duration) (Timestamp, error) {
+	if clock == nil {
+		clock = SystemClock
+	}
+
+	created := clock.Now().UTC().Truncate(time.Millisecond)
+	return &timestamp{
+		Created: created,
+		Expires: created.Add(ttl),
+	}, nil
+}
+
+func NewTimestampNode(context xml.Context) (xml.Node, error) {
+	return NewTimestamp(context)
+}
+
+func (node *timestamp) GetId() string {
+	return node.Id
+}
+
+func (node *timestamp) SetId(id string) {
+	node.Id = id
+}
+
+func (node *timestamp) GetCreated() time.Time {
+	return node.Created
+}
+
+func (node *timestamp) SetCreated(created time.Time) {
+	node.Created = created
+}
+
+func (node *timestamp) GetExpires() time.Time {
+	return node.Expires
+}
+
+func (node *timestamp) SetExpires(expires time.Time) {
+	node.Expires = expires
+}
+
+func (node *timestamp) Validate(clock Clock, skew time.Duration) error {
+	if clock == nil {
+		clock = SystemClock
+	}
+	now := clock.Now()
+
+	if !node.Created.IsZero() && !node.Expires.IsZero() && node.Expires.Before(node.Created) {
+		return ErrTimestampInvalid
+	}
+	if !node.Created.IsZero() && node.Created.After(now.Add(skew)) {
+		return ErrTimestampNotYetValid
+	}
+	if !node.Expires.IsZero() && !node.Expires.After(now.Add(-skew)) {
+		return ErrTimestampExpired
+	}
+
+	return nil
+}
+
+func (node *timestamp) LoadXml(context xml.Context, el *etree.Element) error {
+	err := xml.ValidateElement(el, "Timestamp", WsuNamespace)
+	if err != nil {
+		return err
+	}
+
+	node.SetId(GetWsuId(context, el))
+	node.SetCreated(time.Time{})
+	node.SetExpires(time.Time{})
+
+	createdEl, err := xml.GetOptionalSingleChildElement(el, "Created", WsuNamespace)
+	if err != nil {
+		return err
+	}
+	if createdEl != nil {
+		created, err := ParseDateTime(createdEl.Text())
+		if err != nil {
+			return err
+		}
+		node.SetCreated(created)
+	}
+
+	expiresEl, err := xml.GetOptionalSingleChildElement(el, "Expires", WsuNamespace)
+	if err != nil {
+		return err
+	}
+	if expiresEl != nil {
+		expires, err := ParseDateTime(expiresEl.Text())
+		if err != nil {
+			return err
+		}
+		node.SetExpires(expires)
+	}
+
+	return nil
+}
+
+func (node *timestamp) GetXml(context xml.Context) (*etree.Element, error) {
+	err := generateMissingId(context, node, "Timestamp")
+	if err != nil {
+		return nil, err
+	}
+
+	el := etree.NewElement("Timestamp")
+	el.Space = context.GetNamespacePrefix(WsuNamespace)
+
+	if node.GetId() != "" {
+		SetWsuId(context, el, node.GetId())
+	}
+	if !node.GetCreated().IsZero() {
+		createdEl := el.CreateElement("Created")
+		createdEl.Space = context.GetNamespacePrefix(WsuNamespace)
+		createdEl.SetText(FormatDateTime(node.GetCreated()))
+	}
+	if !node.GetExpires().IsZero() {
+		expiresEl := el.CreateElement("Expires")
+		expiresEl.Space = context.GetNamespacePrefix(WsuNamespace)
+		expiresEl.SetText(FormatDateTime(node.GetExpires()))
+	}
+
+	return el, nil
+}
@@ -0,0 +1,186 @@
+package xmlsecurity
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/beevik/etree"
+	"github.com/deb-ict/go-xml"
+)
+
+func Test_Timestamp_LoadXml_InvalidElement(t *testing.T) {
+	// Create test case XML
+	testCaseXml := fmt.Sprintf(
+		`<wsu:InvalidTag xmlns:wsu="%s"/>`,
+		WsuNamespace,
+	)
+
+	// Prepare the test case
+	testCaseDocument := etree.NewDocument()
+	err := testCaseDocument.ReadFromString(testCaseXml)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCaseContext := xml.NewContext(testCaseDocument)
+
+	// Create test case Timestamp
+	testCaseTimestamp, err := NewTimestamp(testCaseContext)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Load test case Timestamp
+	err = testCaseTimestamp.LoadXml(testCaseContext, testCaseDocument.Root())
+	if err != xml.ErrInvalidElementTag {
+		t.Fatal(err)
+	}
+}
+
+func Test_Timestamp_LoadXml(t *testing.T) {
+	// Create test case XML
+	testCaseXml := fmt.Sprintf(
+		`<wsu:Timestamp xmlns:wsu="%s" wsu:Id="TS-1"><wsu:Created>2024-03-01T10:15:30.250Z</wsu:Created><wsu:Expires>2024-03-01T12:20:30+02:00</wsu:Expires></wsu:Timestamp>`,
+		WsuNamespace,
+	)
+
+	// Prepare the test case
+	testCaseDocument := etree.NewDocument()
+	err := testCaseDocument.ReadFromString(testCaseXml)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCaseContext := xml.NewContext(testCaseDocument)
+	testCaseContext.SetNamespacePrefix("wsu", WsuNamespace)
+
+	// Create test case Timestamp
+	testCaseTimestamp, err := NewTimestamp(testCaseContext)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Load test case Timestamp
+	err = testCaseTimestamp.LoadXml(testCaseContext, testCaseDocument.Root())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Validate test case Timestamp
+	expectedCreated := time.Date(2024, 3, 1, 10, 15, 30, 250000000, time.UTC)
+	expectedExpires := time.Date(2024, 3, 1, 10, 20, 30, 0, time.UTC)
+	if testCaseTimestamp.GetId() != "TS-1" {
+		t.Fatalf("Timestamp.Id = %s; want TS-1", testCaseTimestamp.GetId())
+	}
+	if !testCaseTimestamp.GetCreated().Equal(expectedCreated) {
+		t.Fatalf("Timestamp.Created = %s; want %s", testCaseTimestamp.GetCreated(), expectedCreated)
+	}
+	if !testCaseTimestamp.GetExpires().Equal(expectedExpires) {
+		t.Fatalf("Timestamp.Expires = %s; want %s", testCaseTimestamp.GetExpires(), expectedExpires)
+	}
+}
+
+func Test_Timestamp_LoadXml_InvalidDateTime(t *testing.T) {
+	// Create test case XML
+	testCaseXml := fmt.Sprintf(
+		`<wsu:Timestamp xmlns:wsu="%s"><wsu:Created>yesterday</wsu:Created></wsu:Timestamp>`,
+		WsuNamespace,
+	)
+
+	// Prepare the test case
+	testCaseDocument := etree.NewDocument()
+	err := testCaseDocument.ReadFromString(testCaseXml)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCaseContext := xml.NewContext(testCaseDocument)
+
+	// Create test case Timestamp
+	testCaseTimestamp, err := NewTimestamp(testCaseContext)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Load test case Timestamp
+	err = testCaseTimestamp.LoadXml(testCaseContext, testCaseDocument.Root())
+	if err != ErrInvalidDateTime {
+		t.Fatal(err)
+	}
+}
+
+func Test_Timestamp_GetXml(t *testing.T) {
+	// Create test case XML
+	testCaseXml := fmt.Sprintf(
+		`<wsu:Timestamp xmlns:wsu="%s" wsu:Id="TS-1"><wsu:Created>2024-03-01T10:15:30.000Z</wsu:Created><wsu:Expires>2024-03-01T10:20:30.000Z</wsu:Expires></wsu:Timestamp>`,
+		WsuNamespace,
+	)
+
+	// Prepare the test case
+	testCaseDocument := etree.NewDocument()
+	testCaseContext := xml.NewContext(testCaseDocument)
+	testCaseContext.SetNamespacePrefix("wsu", WsuNamespace)
+
+	// Create test case Timestamp
+	clock := ClockFunc(func() time.Time {
+		return time.Date(2024, 3, 1, 10, 15, 30, 0, time.UTC)
+	})
+	testCaseTimestamp, err := NewTimestampWithTtl(testCaseContext, clock, 5*time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCaseTimestamp.SetId("TS-1")
+
+	// Get test case Timestamp XML
+	testCaseTimestampElement, err := testCaseTimestamp.GetXml(testCaseContext)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Get the test case XML
+	testCaseDocument.SetRoot(testCaseTimestampElement)
+	resultXml, err := testCaseDocument.WriteToString()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Validate test case XML
+	if resultXml != testCaseXml {
+		t.Fatalf("Timestamp.GetXml() = %s; want %s", resultXml, testCaseXml)
+	}
+}
+
+func Test_Timestamp_Validate(t *testing.T) {
+	created := time.Date(2024, 3, 1, 10, 15, 30, 0, time.UTC)
+	expires := created.Add(5 * time.Minute)
+
+	// Create test case
+	testCase := []struct {
+		now      time.Time
+		skew     time.Duration
+		created  time.Time
+		expires  time.Time
+		expected error
+	}{
+		{now: created.Add(time.Minute), created: created, expires: expires, expected: nil},
+		{now: created.Add(-time.Minute), created: created, expires: expires, expected: ErrTimestampNotYetValid},
+		{now: created.Add(-time.Minute), skew: 2 * time.Minute, created: created, expires: expires, expected: nil},
+		{now: expires, created: created, expires: expires, expected: ErrTimestampExpired},
+		{now: expires.Add(time.Minute), skew: 2 * time.Minute, created: created, expires: expires, expected: nil},
+		{now: created, created: expires, expires: created, expected: ErrTimestampInvalid},
+		{now: created.Add(time.Hour), created: created, expected: nil},
+	}
+
+	for _, tc := range testCase {
+		testCaseTimestamp := &timestamp{
+			Created: tc.created,
+			Expires: tc.expires,
+		}
+		clock := ClockFunc(func() time.Time {
+			return tc.now
+		})
+
+		err := testCaseTimestamp.Validate(clock, tc.skew)
+		if err != tc.expected {
+			t.Fatalf("Timestamp.Validate() at %s = %v; want %v", tc.now, err, tc.expected)
+		}
+	}
+}
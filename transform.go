@@ -0,0 +1,95 @@
+package xmlsecurity
+
+import (
+	"strings"
+
+	"github.com/beevik/etree"
+	"github.com/deb-ict/go-xml"
+)
+
+const (
+	EnvelopedSignatureAlgorithm string = "http://www.w3.org/2000/09/xmldsig#enveloped-signature"
+)
+
+type Transform interface {
+	xml.Node
+	GetAlgorithm() string
+	SetAlgorithm(algorithm string)
+	GetInclusiveNamespaces() []string
+	SetInclusiveNamespaces(prefixes []string)
+	GetCanonicalizer() (Canonicalizer, error)
+}
+
+type transform struct {
+	Algorithm           string
+	InclusiveNamespaces []string
+}
+
+func NewTransform(context xml.Context) (Transform, error) {
+	return &transform{}, nil
+}
+
+func NewTransformNode(context xml.Context) (xml.Node, error) {
+	return NewTransform(context)
+}
+
+func (node *transform) GetAlgorithm() string {
+	return node.Algorithm
+}
+
+func (node *transform) SetAlgorithm(algorithm string) {
+	node.Algorithm = algorithm
+}
+
+func (node *transform) GetInclusiveNamespaces() []string {
+	return node.InclusiveNamespaces
+}
+
+func (node *transform) SetInclusiveNamespaces(prefixes []string) {
+	node.InclusiveNamespaces = prefixes
+}
+
+func (node *transform) GetCanonicalizer() (Canonicalizer, error) {
+	return NewCanonicalizer(node.GetAlgorithm(), node.GetInclusiveNamespaces())
+}
+
+func (node *transform) LoadXml(context xml.Context, el *etree.Element) error {
+	err := xml.ValidateElement(el, "Transform", DsigNamespace)
+	if err != nil {
+		return err
+	}
+
+	node.SetAlgorithm(el.SelectAttrValue("Algorithm", ""))
+	node.SetInclusiveNamespaces(loadInclusiveNamespaces(context, el))
+
+	return nil
+}
+
+func (node *transform) GetXml(context xml.Context) (*etree.Element, error) {
+	el := etree.NewElement("Transform")
+	el.Space = context.GetNamespacePrefix(DsigNamespace)
+
+	el.CreateAttr("Algorithm", node.GetAlgorithm())
+	addInclusiveNamespaces(context, el, node.GetInclusiveNamespaces())
+
+	return el, nil
+}
+
+func loadInclusiveNamespaces(context xml.Context, el *etree.Element) []string {
+	for _, child := range el.ChildElements() {
+		if !isElement(context, child, "InclusiveNamespaces", ExcC14NNamespace) {
+			continue
+		}
+		return strings.Fields(child.SelectAttrValue("PrefixList", ""))
+	}
+	return nil
+}
+
+func addInclusiveNamespaces(context xml.Context, el *etree.Element, prefixes []string) {
+	if len(prefixes) == 0 {
+		return
+	}
+	inclusiveNamespacesEl := el.CreateElement("InclusiveNamespaces")
+	inclusiveNamespacesEl.Space = context.GetNamespacePrefix(ExcC14NNamespace)
+	inclusiveNamespacesEl.CreateAttr("PrefixList", strings.Join(prefixes, " "))
+}
@@ -0,0 +1,439 @@
+package xmlsecurity
+
+import (
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/beevik/etree"
+	"github.com/deb-ict/go-xml"
+)
+
+const (
+	PasswordTextType   string = "http://docs.oasis-open.org/wss/2004/01/oasis-200401-wss-username-token-profile-1.0#PasswordText"
+	PasswordDigestType string = "http://docs.oasis-open.org/wss/2004/01/oasis-200401-wss-username-token-profile-1.0#PasswordDigest"
+)
+
+const (
+	SaltMacPrefix        byte = 0x01
+	SaltEncryptionPrefix byte = 0x02
+	DefaultIteration     int  = 1000
+	MinIteration         int  = 1000
+	MaxIteration         int  = 100000
+)
+
+var (
+	ErrSaltNotAvailable        = errors.New("salt not available")
+	ErrInvalidPassword         = errors.New("invalid password")
+	ErrUnsupportedPasswordType = errors.New("unsupported password type")
+	ErrUnsupportedEncodingType = errors.New("unsupported encoding type")
+	ErrIterationTooLow         = errors.New("iteration count below minimum")
+	ErrIterationTooHigh        = errors.New("iteration count exceeds maximum")
+)
+
+type PasswordProvider interface {
+	GetPassword(username string) (string, error)
+}
+
+type PasswordProviderFunc func(username string) (string, error)
+
+type UsernameToken interface {
+	xml.Node
+	GetId() string
+	SetId(id string)
+	GetUsername() string
+	SetUsername(username string)
+	GetPassword() string
+	SetPassword(password string)
+	GetPasswordType() string
+	SetPasswordType(passwordType string)
+	GetNonce() []byte
+	SetNonce(nonce []byte)
+	GetCreated() time.Time
+	SetCreated(created time.Time)
+	GetSalt() []byte
+	SetSalt(salt []byte)
+	GetIteration() int
+	SetIteration(iteration int)
+	VerifyPassword(provider PasswordProvider) error
+	DeriveKey(password string) ([]byte, error)
+	DeriveKeyWithMinIteration(password string, minIteration int) ([]byte, error)
+}
+
+type usernameToken struct {
+	Id           string
+	Username     string
+	Password     string
+	PasswordType string
+	Nonce        []byte
+	Created      time.Time
+	Salt         []byte
+	Iteration    int
+	createdValue string
+}
+
+func NewUsernameToken(context xml.Context) (UsernameToken, error) {
+	return &usernameToken{}, nil
+}
+
+func NewUsernameTokenNode(context xml.Context) (xml.Node, error) {
+	return NewUsernameToken(context)
+}
+
+func NewUsernameTokenWithPasswordText(context xml.Context, username string, password string) (UsernameToken, error) {
+	return &usernameToken{
+		Username:     username,
+		Password:     password,
+		PasswordType: PasswordTextType,
+	}, nil
+}
+
+func NewUsernameTokenWithPasswordDigest(context xml.Context, clock Clock, username string, password string) (UsernameToken, error) {
+	if clock == nil {
+		clock = SystemClock
+	}
+
+	nonce := make([]byte, 16)
+	_, err := rand.Read(nonce)
+	if err != nil {
+		return nil, err
+	}
+	created := clock.Now().UTC().Truncate(time.Millisecond)
+
+	return &usernameToken{
+		Username:     username,
+		Password:     ComputePasswordDigest(nonce, FormatDateTime(created), password),
+		PasswordType: PasswordDigestType,
+		Nonce:        nonce,
+		Created:      created,
+	}, nil
+}
+
+func NewUsernameTokenWithDerivedKey(context xml.Context, username string, iteration int) (UsernameToken, error) {
+	salt, err := GenerateSalt(SaltMacPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	return &usernameToken{
+		Username:  username,
+		Salt:      salt,
+		Iteration: iteration,
+	}, nil
+}
+
+func GenerateSalt(prefix byte) ([]byte, error) {
+	salt := make([]byte, 16)
+	_, err := rand.Read(salt[1:])
+	if err != nil {
+		return nil, err
+	}
+	salt[0] = prefix
+	return salt, nil
+}
+
+func DeriveKey(password string, salt []byte, iteration int) ([]byte, error) {
+	if iteration <= 0 {
+		iteration = DefaultIteration
+	}
+	if iteration > MaxIteration {
+		return nil, ErrIterationTooHigh
+	}
+
+	hash := sha1.New()
+	hash.Write([]byte(password))
+	hash.Write(salt)
+	key := hash.Sum(nil)
+	for i := 1; i < iteration; i++ {
+		sum := sha1.Sum(key)
+		key = sum[:]
+	}
+	return key, nil
+}
+
+func ComputePasswordDigest(nonce []byte, created string, password string) string {
+	hash := sha1.New()
+	hash.Write(nonce)
+	hash.Write([]byte(created))
+	hash.Write([]byte(password))
+	return base64.StdEncoding.EncodeToString(hash.Sum(nil))
+}
+
+func (f PasswordProviderFunc) GetPassword(username string) (string, error) {
+	return f(username)
+}
+
+func (node *usernameToken) GetId() string {
+	return node.Id
+}
+
+func (node *usernameToken) SetId(id string) {
+	node.Id = id
+}
+
+func (node *usernameToken) GetUsername() string {
+	return node.Username
+}
+
+func (node *usernameToken) SetUsername(username string) {
+	node.Username = username
+}
+
+func (node *usernameToken) GetPassword() string {
+	return node.Password
+}
+
+func (node *usernameToken) SetPassword(password string) {
+	node.Password = password
+}
+
+func (node *usernameToken) GetPasswordType() string {
+	return node.PasswordType
+}
+
+func (node *usernameToken) SetPasswordType(passwordType string) {
+	node.PasswordType = passwordType
+}
+
+func (node *usernameToken) GetNonce() []byte {
+	return node.Nonce
+}
+
+func (node *usernameToken) SetNonce(nonce []byte) {
+	node.Nonce = nonce
+}
+
+func (node *usernameToken) GetCreated() time.Time {
+	return node.Created
+}
+
+func (node *usernameToken) SetCreated(created time.Time) {
+	node.Created = created
+	node.createdValue = ""
+}
+
+func (node *usernameToken) GetSalt() []byte {
+	return node.Salt
+}
+
+func (node *usernameToken) SetSalt(salt []byte) {
+	node.Salt = salt
+}
+
+func (node *usernameToken) GetIteration() int {
+	return node.Iteration
+}
+
+func (node *usernameToken) SetIteration(iteration int) {
+	node.Iteration = iteration
+}
+
+func (node *usernameToken) DeriveKey(password string) ([]byte, error) {
+	return node.DeriveKeyWithMinIteration(password, MinIteration)
+}
+
+func (node *usernameToken) DeriveKeyWithMinIteration(password string, minIteration int) ([]byte, error) {
+	if len(node.GetSalt()) == 0 {
+		return nil, ErrSaltNotAvailable
+	}
+
+	// Tokens without an iteration count use the default
+	iteration := node.GetIteration()
+	if iteration <= 0 {
+		iteration = DefaultIteration
+	}
+	if iteration < minIteration {
+		return nil, ErrIterationTooLow
+	}
+	return DeriveKey(password, node.GetSalt(), iteration)
+}
+
+func (node *usernameToken) VerifyPassword(provider PasswordProvider) error {
+	// Tokens without a password never verify
+	if node.GetPassword() == "" {
+		return ErrInvalidPassword
+	}
+	password, err := provider.GetPassword(node.GetUsername())
+	if err != nil {
+		return err
+	}
+	if password == "" {
+		return ErrInvalidPassword
+	}
+
+	var expected string
+	switch node.GetPasswordType() {
+	case "", PasswordTextType:
+		expected = password
+	case PasswordDigestType:
+		// Without a nonce and created time the digest can be replayed
+		if len(node.GetNonce()) == 0 || node.getCreatedValue() == "" {
+			return ErrInvalidPassword
+		}
+		expected = ComputePasswordDigest(node.GetNonce(), node.getCreatedValue(), password)
+	default:
+		return ErrUnsupportedPasswordType
+	}
+
+	if subtle.ConstantTimeCompare([]byte(expected), []byte(node.GetPassword())) != 1 {
+		return ErrInvalidPassword
+	}
+	return nil
+}
+
+func (node *usernameToken) getCreatedValue() string {
+	// The digest is computed over the literal value that was sent
+	if node.createdValue != "" {
+		return node.createdValue
+	}
+	if node.Created.IsZero() {
+		return ""
+	}
+	return FormatDateTime(node.Created)
+}
+
+func (node *usernameToken) LoadXml(context xml.Context, el *etree.Element) error {
+	err := xml.ValidateElement(el, "UsernameToken", WsseNamespace)
+	if err != nil {
+		return err
+	}
+
+	node.SetId(GetWsuId(context, el))
+
+	usernameEl, err := xml.GetSingleChildElement(el, "Username", WsseNamespace)
+	if err != nil {
+		return err
+	}
+	node.SetUsername(usernameEl.Text())
+
+	node.SetPassword("")
+	node.SetPasswordType("")
+	passwordEl, err := xml.GetOptionalSingleChildElement(el, "Password", WsseNamespace)
+	if err != nil {
+		return err
+	}
+	if passwordEl != nil {
+		node.SetPassword(passwordEl.Text())
+		node.SetPasswordType(passwordEl.SelectAttrValue("Type", PasswordTextType))
+	}
+
+	node.SetNonce(nil)
+	nonceEl, err := xml.GetOptionalSingleChildElement(el, "Nonce", WsseNamespace)
+	if err != nil {
+		return err
+	}
+	if nonceEl != nil {
+		encodingType := nonceEl.SelectAttrValue("EncodingType", Base64BinaryEncodingType)
+		if encodingType != Base64BinaryEncodingType {
+			return ErrUnsupportedEncodingType
+		}
+		nonce, err := base64.StdEncoding.DecodeString(nonceEl.Text())
+		if err != nil {
+			return err
+		}
+		node.SetNonce(nonce)
+	}
+
+	node.SetCreated(time.Time{})
+	createdEl, err := xml.GetOptionalSingleChildElement(el, "Created", WsuNamespace)
+	if err != nil {
+		return err
+	}
+	if createdEl != nil {
+		created, err := ParseDateTime(createdEl.Text())
+		if err != nil {
+			return err
+		}
+		node.SetCreated(created)
+		node.createdValue = createdEl.Text()
+	}
+
+	node.SetSalt(nil)
+	saltEl, err := xml.GetOptionalSingleChildElement(el, "Salt", Wsse11Namespace)
+	if err != nil {
+		return err
+	}
+	if saltEl != nil {
+		salt, err := base64.StdEncoding.DecodeString(saltEl.Text())
+		if err != nil {
+			return err
+		}
+		node.SetSalt(salt)
+	}
+
+	node.SetIteration(0)
+	iterationEl, err := xml.GetOptionalSingleChildElement(el, "Iteration", Wsse11Namespace)
+	if err != nil {
+		return err
+	}
+	if iterationEl != nil {
+		iteration, err := strconv.Atoi(strings.TrimSpace(iterationEl.Text()))
+		if err != nil {
+			return err
+		}
+		if iteration < 1 {
+			return ErrIterationTooLow
+		}
+		if iteration > MaxIteration {
+			return ErrIterationTooHigh
+		}
+		node.SetIteration(iteration)
+	}
+
+	return nil
+}
+
+func (node *usernameToken) GetXml(context xml.Context) (*etree.Element, error) {
+	err := generateMissingId(context, node, "UsernameToken")
+	if err != nil {
+		return nil, err
+	}
+
+	el := etree.NewElement("UsernameToken")
+	el.Space = context.GetNamespacePrefix(WsseNamespace)
+
+	if node.GetId() != "" {
+		SetWsuId(context, el, node.GetId())
+	}
+
+	usernameEl := el.CreateElement("Username")
+	usernameEl.Space = context.GetNamespacePrefix(WsseNamespace)
+	usernameEl.SetText(node.GetUsername())
+
+	if node.GetPassword() != "" {
+		passwordEl := el.CreateElement("Password")
+		passwordEl.Space = context.GetNamespacePrefix(WsseNamespace)
+		if node.GetPasswordType() != "" {
+			passwordEl.CreateAttr("Type", node.GetPasswordType())
+		}
+		passwordEl.SetText(node.GetPassword())
+	}
+	if len(node.GetNonce()) > 0 {
+		nonceEl := el.CreateElement("Nonce")
+		nonceEl.Space = context.GetNamespacePrefix(WsseNamespace)
+		nonceEl.CreateAttr("EncodingType", Base64BinaryEncodingType)
+		nonceEl.SetText(base64.StdEncoding.EncodeToString(node.GetNonce()))
+	}
+	if !node.GetCreated().IsZero() {
+		createdEl := el.CreateElement("Created")
+		createdEl.Space = context.GetNamespacePrefix(WsuNamespace)
+		createdEl.SetText(node.getCreatedValue())
+	}
+	if len(node.GetSalt()) > 0 {
+		saltEl := el.CreateElement("Salt")
+		saltEl.Space = context.GetNamespacePrefix(Wsse11Namespace)
+		saltEl.SetText(base64.StdEncoding.EncodeToString(node.GetSalt()))
+	}
+	if node.GetIteration() > 0 {
+		iterationEl := el.CreateElement("Iteration")
+		iterationEl.Space = context.GetNamespacePrefix(Wsse11Namespace)
+		iterationEl.SetText(strconv.Itoa(node.GetIteration()))
+	}
+
+	return el, nil
+}
@@ -0,0 +1,437 @@
+package xmlsecurity
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/beevik/etree"
+	"github.com/deb-ict/go-xml"
+)
+
+func Test_UsernameToken_LoadXml_InvalidElement(t *testing.T) {
+	// Create test case XML
+	testCaseXml := fmt.Sprintf(
+		`<wsse:InvalidTag xmlns:wsse="%s"/>`,
+		WsseNamespace,
+	)
+
+	// Prepare the test case
+	testCaseDocument := etree.NewDocument()
+	err := testCaseDocument.ReadFromString(testCaseXml)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCaseContext := xml.NewContext(testCaseDocument)
+
+	// Create test case UsernameToken
+	testCaseUsernameToken, err := NewUsernameToken(testCaseContext)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Load test case UsernameToken
+	err = testCaseUsernameToken.LoadXml(testCaseContext, testCaseDocument.Root())
+	if err != xml.ErrInvalidElementTag {
+		t.Fatal(err)
+	}
+}
+
+func Test_UsernameToken_LoadXml_PasswordDigest(t *testing.T) {
+	// Create test case XML
+	testCaseXml := fmt.Sprintf(
+		`<wsse:UsernameToken wsu:Id="UT-1" xmlns:wsse="%s" xmlns:wsu="%s">`+
+			`<wsse:Username>user</wsse:Username>`+
+			`<wsse:Password Type="%s">tuOSpGlFlIXsozq4HFNeeGeFLEI=</wsse:Password>`+
+			`<wsse:Nonce EncodingType="%s">LKqI6G/AikKCQrN0zqZFlg==</wsse:Nonce>`+
+			`<wsu:Created>2010-09-16T07:50:45Z</wsu:Created>`+
+			`</wsse:UsernameToken>`,
+		WsseNamespace,
+		WsuNamespace,
+		PasswordDigestType,
+		Base64BinaryEncodingType,
+	)
+
+	// Prepare the test case
+	testCaseDocument := etree.NewDocument()
+	err := testCaseDocument.ReadFromString(testCaseXml)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCaseContext := xml.NewContext(testCaseDocument)
+	ConfigureContext(testCaseContext)
+
+	// Create test case UsernameToken
+	testCaseUsernameToken, err := NewUsernameToken(testCaseContext)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Load test case UsernameToken
+	err = testCaseUsernameToken.LoadXml(testCaseContext, testCaseDocument.Root())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Validate test case UsernameToken
+	if testCaseUsernameToken.GetId() != "UT-1" {
+		t.Fatalf("UsernameToken.Id = %s; want UT-1", testCaseUsernameToken.GetId())
+	}
+	if testCaseUsernameToken.GetUsername() != "user" {
+		t.Fatalf("UsernameToken.Username = %s; want user", testCaseUsernameToken.GetUsername())
+	}
+	if testCaseUsernameToken.GetPasswordType() != PasswordDigestType {
+		t.Fatalf("UsernameToken.PasswordType = %s; want %s", testCaseUsernameToken.GetPasswordType(), PasswordDigestType)
+	}
+	if len(testCaseUsernameToken.GetNonce()) != 16 {
+		t.Fatalf("len(UsernameToken.Nonce) = %d; want 16", len(testCaseUsernameToken.GetNonce()))
+	}
+	if !testCaseUsernameToken.GetCreated().Equal(time.Date(2010, 9, 16, 7, 50, 45, 0, time.UTC)) {
+		t.Fatalf("UsernameToken.Created = %s", testCaseUsernameToken.GetCreated())
+	}
+
+	// Verify the test case password
+	provider := PasswordProviderFunc(func(username string) (string, error) {
+		return "userpassword", nil
+	})
+	err = testCaseUsernameToken.VerifyPassword(provider)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	invalidProvider := PasswordProviderFunc(func(username string) (string, error) {
+		return "wrong", nil
+	})
+	err = testCaseUsernameToken.VerifyPassword(invalidProvider)
+	if err != ErrInvalidPassword {
+		t.Fatalf("UsernameToken.VerifyPassword() = %v; want %v", err, ErrInvalidPassword)
+	}
+}
+
+func Test_UsernameToken_VerifyPassword_PasswordText(t *testing.T) {
+	errUnknownUser := errors.New("unknown user")
+	provider := PasswordProviderFunc(func(username string) (string, error) {
+		if username != "user" {
+			return "", errUnknownUser
+		}
+		return "secret", nil
+	})
+
+	// Create test case
+	testCase := []struct {
+		username string
+		password string
+		expected error
+	}{
+		{username: "user", password: "secret", expected: nil},
+		{username: "user", password: "other", expected: ErrInvalidPassword},
+		{username: "other", password: "secret", expected: errUnknownUser},
+	}
+
+	for _, tc := range testCase {
+		testCaseUsernameToken, err := NewUsernameTokenWithPasswordText(nil, tc.username, tc.password)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = testCaseUsernameToken.VerifyPassword(provider)
+		if err != tc.expected {
+			t.Fatalf("UsernameToken.VerifyPassword() = %v; want %v", err, tc.expected)
+		}
+	}
+}
+
+func Test_UsernameToken_VerifyPassword_Errors(t *testing.T) {
+	passwords := map[string]string{
+		"user": "secret",
+	}
+	provider := PasswordProviderFunc(func(username string) (string, error) {
+		return passwords[username], nil
+	})
+	created := time.Date(2024, 3, 1, 10, 15, 30, 0, time.UTC)
+	nonce := []byte("0123456789abcdef")
+
+	// Create test case
+	testCase := []struct {
+		name         string
+		username     string
+		password     string
+		passwordType string
+		nonce        []byte
+		created      time.Time
+	}{
+		{name: "no password", username: "mallory"},
+		{name: "empty password text", username: "mallory", password: "", passwordType: PasswordTextType},
+		{name: "unknown user", username: "mallory", password: "secret", passwordType: PasswordTextType},
+		{name: "digest without nonce", username: "user", password: ComputePasswordDigest(nil, FormatDateTime(created), "secret"), passwordType: PasswordDigestType, created: created},
+		{name: "digest without created", username: "user", password: ComputePasswordDigest(nonce, "", "secret"), passwordType: PasswordDigestType, nonce: nonce},
+		{name: "digest without nonce and created", username: "user", password: ComputePasswordDigest(nil, "", "secret"), passwordType: PasswordDigestType},
+	}
+
+	for _, tc := range testCase {
+		testCaseUsernameToken, err := NewUsernameToken(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		testCaseUsernameToken.SetUsername(tc.username)
+		testCaseUsernameToken.SetPassword(tc.password)
+		testCaseUsernameToken.SetPasswordType(tc.passwordType)
+		testCaseUsernameToken.SetNonce(tc.nonce)
+		testCaseUsernameToken.SetCreated(tc.created)
+
+		err = testCaseUsernameToken.VerifyPassword(provider)
+		if err != ErrInvalidPassword {
+			t.Fatalf("UsernameToken.VerifyPassword(%s) = %v; want %v", tc.name, err, ErrInvalidPassword)
+		}
+	}
+}
+
+func Test_UsernameToken_GetXml_PasswordDigest(t *testing.T) {
+	// Prepare the test case
+	testCaseDocument := etree.NewDocument()
+	testCaseContext := xml.NewContext(testCaseDocument)
+	ConfigureContext(testCaseContext)
+
+	// Create test case UsernameToken
+	clock := ClockFunc(func() time.Time {
+		return time.Date(2024, 3, 1, 10, 15, 30, 0, time.UTC)
+	})
+	testCaseUsernameToken, err := NewUsernameTokenWithPasswordDigest(testCaseContext, clock, "user", "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Get test case UsernameToken XML
+	testCaseUsernameTokenElement, err := testCaseUsernameToken.GetXml(testCaseContext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCaseUsernameTokenElement.CreateAttr("xmlns:wsse", WsseNamespace)
+	testCaseUsernameTokenElement.CreateAttr("xmlns:wsu", WsuNamespace)
+	testCaseDocument.SetRoot(testCaseUsernameTokenElement)
+
+	// Reload the test case UsernameToken
+	loadedUsernameToken, err := NewUsernameToken(testCaseContext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = loadedUsernameToken.LoadXml(testCaseContext, testCaseDocument.Root())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Validate the loaded UsernameToken
+	if createdEl := testCaseDocument.FindElement("//wsu:Created"); createdEl == nil || createdEl.Text() != "2024-03-01T10:15:30.000Z" {
+		t.Fatal("UsernameToken.GetXml() did not emit the expected wsu:Created")
+	}
+	if loadedUsernameToken.GetPasswordType() != PasswordDigestType {
+		t.Fatalf("UsernameToken.PasswordType = %s; want %s", loadedUsernameToken.GetPasswordType(), PasswordDigestType)
+	}
+	provider := PasswordProviderFunc(func(username string) (string, error) {
+		return "secret", nil
+	})
+	err = loadedUsernameToken.VerifyPassword(provider)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_UsernameToken_LoadXml_DerivedKey(t *testing.T) {
+	// Create test case XML
+	testCaseXml := fmt.Sprintf(
+		`<wsse:UsernameToken xmlns:wsse="%s" xmlns:wsse11="%s">`+
+			`<wsse:Username>user</wsse:Username>`+
+			`<wsse11:Salt>AQECAwQFBgcICQoLDA0ODw==</wsse11:Salt>`+
+			`<wsse11:Iteration>1000</wsse11:Iteration>`+
+			`</wsse:UsernameToken>`,
+		WsseNamespace,
+		Wsse11Namespace,
+	)
+
+	// Prepare the test case
+	testCaseDocument := etree.NewDocument()
+	err := testCaseDocument.ReadFromString(testCaseXml)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCaseContext := xml.NewContext(testCaseDocument)
+	ConfigureContext(testCaseContext)
+
+	// Create test case UsernameToken
+	testCaseUsernameToken, err := NewUsernameToken(testCaseContext)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Load test case UsernameToken
+	err = testCaseUsernameToken.LoadXml(testCaseContext, testCaseDocument.Root())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Validate test case UsernameToken
+	if len(testCaseUsernameToken.GetSalt()) != 16 || testCaseUsernameToken.GetSalt()[0] != SaltMacPrefix {
+		t.Fatalf("UsernameToken.Salt = %x", testCaseUsernameToken.GetSalt())
+	}
+	if testCaseUsernameToken.GetIteration() != 1000 {
+		t.Fatalf("UsernameToken.Iteration = %d; want 1000", testCaseUsernameToken.GetIteration())
+	}
+
+	// Derive the test case key
+	key, err := testCaseUsernameToken.DeriveKey("password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hex.EncodeToString(key) != "fbd071cbf507e90c4dc68a51effbe43fb0101ee3" {
+		t.Fatalf("UsernameToken.DeriveKey() = %x", key)
+	}
+}
+
+func Test_UsernameToken_DeriveKey_NoSalt(t *testing.T) {
+	testCaseUsernameToken, err := NewUsernameTokenWithPasswordText(nil, "user", "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = testCaseUsernameToken.DeriveKey("secret")
+	if err != ErrSaltNotAvailable {
+		t.Fatalf("UsernameToken.DeriveKey() = %v; want %v", err, ErrSaltNotAvailable)
+	}
+}
+
+func Test_UsernameToken_GetXml_DerivedKey(t *testing.T) {
+	// Prepare the test case
+	testCaseDocument := etree.NewDocument()
+	testCaseContext := xml.NewContext(testCaseDocument)
+	ConfigureContext(testCaseContext)
+
+	// Create test case UsernameToken
+	testCaseUsernameToken, err := NewUsernameTokenWithDerivedKey(testCaseContext, "user", 500)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCaseUsernameToken.SetSalt([]byte{0x01, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0a, 0x0b, 0x0c, 0x0d, 0x0e, 0x0f})
+
+	// Get test case UsernameToken XML
+	testCaseUsernameTokenElement, err := testCaseUsernameToken.GetXml(testCaseContext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCaseUsernameTokenElement.CreateAttr("xmlns:wsse", WsseNamespace)
+	testCaseUsernameTokenElement.CreateAttr("xmlns:wsse11", Wsse11Namespace)
+
+	// Get the test case XML
+	testCaseDocument.SetRoot(testCaseUsernameTokenElement)
+	resultXml, err := testCaseDocument.WriteToString()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Validate test case XML
+	expectedXml := fmt.Sprintf(
+		`<wsse:UsernameToken xmlns:wsse="%s" xmlns:wsse11="%s">`+
+			`<wsse:Username>user</wsse:Username>`+
+			`<wsse11:Salt>AQECAwQFBgcICQoLDA0ODw==</wsse11:Salt>`+
+			`<wsse11:Iteration>500</wsse11:Iteration>`+
+			`</wsse:UsernameToken>`,
+		WsseNamespace,
+		Wsse11Namespace,
+	)
+	if resultXml != expectedXml {
+		t.Fatalf("UsernameToken.GetXml() = %s; want %s", resultXml, expectedXml)
+	}
+}
+
+func Test_UsernameToken_LoadXml_Iteration_Errors(t *testing.T) {
+	// Create test cases
+	testCases := []struct {
+		iteration string
+		err       error
+	}{
+		{
+			iteration: "2000000000",
+			err:       ErrIterationTooHigh,
+		},
+		{
+			iteration: "100001",
+			err:       ErrIterationTooHigh,
+		},
+		{
+			iteration: "0",
+			err:       ErrIterationTooLow,
+		},
+		{
+			iteration: "-1",
+			err:       ErrIterationTooLow,
+		},
+	}
+
+	for _, tc := range testCases {
+		// Create test case XML
+		testCaseXml := fmt.Sprintf(
+			`<wsse:UsernameToken xmlns:wsse="%s" xmlns:wsse11="%s">`+
+				`<wsse:Username>user</wsse:Username>`+
+				`<wsse11:Salt>AQECAwQFBgcICQoLDA0ODw==</wsse11:Salt>`+
+				`<wsse11:Iteration>%s</wsse11:Iteration>`+
+				`</wsse:UsernameToken>`,
+			WsseNamespace,
+			Wsse11Namespace,
+			tc.iteration,
+		)
+
+		// Prepare the test case
+		testCaseDocument := etree.NewDocument()
+		err := testCaseDocument.ReadFromString(testCaseXml)
+		if err != nil {
+			t.Fatal(err)
+		}
+		testCaseContext := xml.NewContext(testCaseDocument)
+		ConfigureContext(testCaseContext)
+
+		// Load test case UsernameToken
+		testCaseUsernameToken, err := NewUsernameToken(testCaseContext)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = testCaseUsernameToken.LoadXml(testCaseContext, testCaseDocument.Root())
+		if err != tc.err {
+			t.Fatalf("UsernameToken.LoadXml(%s) error = %v; want %v", tc.iteration, err, tc.err)
+		}
+	}
+}
+
+func Test_UsernameToken_DeriveKey_MinIteration(t *testing.T) {
+	testCaseUsernameToken, err := NewUsernameTokenWithDerivedKey(nil, "user", 500)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Derive below the spec minimum
+	_, err = testCaseUsernameToken.DeriveKey("secret")
+	if err != ErrIterationTooLow {
+		t.Fatalf("UsernameToken.DeriveKey() = %v; want %v", err, ErrIterationTooLow)
+	}
+
+	// Derive with an explicit lower minimum
+	key, err := testCaseUsernameToken.DeriveKeyWithMinIteration("secret", 500)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedKey, err := DeriveKey("secret", testCaseUsernameToken.GetSalt(), 500)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(key, expectedKey) {
+		t.Fatalf("UsernameToken.DeriveKeyWithMinIteration() = %x; want %x", key, expectedKey)
+	}
+
+	// Derive above the maximum
+	testCaseUsernameToken.SetIteration(MaxIteration + 1)
+	_, err = testCaseUsernameToken.DeriveKeyWithMinIteration("secret", 1)
+	if err != ErrIterationTooHigh {
+		t.Fatalf("UsernameToken.DeriveKeyWithMinIteration() = %v; want %v", err, ErrIterationTooHigh)
+	}
+}
@@ -6,7 +6,7 @@ import (
 )
 
 func GetWsuId(context xml.Context, el *etree.Element) string {
-	attr := el.SelectAttr(context.GetNamespacePrefix(WsuNamespace) + ":Id")
+	attr := selectWsuIdAttr(context, el)
 	if attr != nil {
 		return attr.Value
 	}
@@ -15,11 +15,16 @@ func GetWsuId(context xml.Context, el *etree.Element) string {
 }
 
 func SetWsuId(context xml.Context, el *etree.Element, id string) {
-	attr := el.SelectAttr(context.GetNamespacePrefix(WsuNamespace) + ":Id")
+	attr := selectWsuIdAttr(context, el)
 	if attr != nil {
 		attr.Value = id
 		return
 	}
 
-	el.CreateAttr(context.GetNamespacePrefix(WsuNamespace)+":Id", id)
+	prefix := declareNamespacePrefix(el, context.GetNamespacePrefix(WsuNamespace), WsuNamespace)
+	el.CreateAttr(prefix+":Id", id)
+}
+
+func selectWsuIdAttr(context xml.Context, el *etree.Element) *etree.Attr {
+	return WsuIdAttribute.selectAttr(context, el)
 }
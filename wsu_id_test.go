@@ -21,6 +21,18 @@ func Test_GetWsuId(t *testing.T) {
 			xml: `<Test xmlns:wsu="` + WsuNamespace + `" Id="123"/>`,
 			id:  "",
 		},
+		{
+			xml: `<Test xmlns:u="` + WsuNamespace + `" u:Id="123"/>`,
+			id:  "123",
+		},
+		{
+			xml: `<Test xmlns:wsu="urn:other" wsu:Id="123"/>`,
+			id:  "",
+		},
+		{
+			xml: `<Test xmlns:wsu="urn:other" xmlns:wsu1="` + WsuNamespace + `" wsu:Id="456" wsu1:Id="123"/>`,
+			id:  "123",
+		},
 	}
 
 	for _, tc := range testCase {
@@ -63,6 +75,26 @@ func Test_SetWsuId(t *testing.T) {
 			originalXml: `<Test xmlns:wsu="` + WsuNamespace + `" Id="456"/>`,
 			expectedXml: `<Test xmlns:wsu="` + WsuNamespace + `" Id="456" wsu:Id="123"/>`,
 		},
+		{
+			newId:       "123",
+			originalXml: `<Test/>`,
+			expectedXml: `<Test xmlns:wsu="` + WsuNamespace + `" wsu:Id="123"/>`,
+		},
+		{
+			newId:       "123",
+			originalXml: `<Test xmlns:u="` + WsuNamespace + `" u:Id="456"/>`,
+			expectedXml: `<Test xmlns:u="` + WsuNamespace + `" u:Id="123"/>`,
+		},
+		{
+			newId:       "123",
+			originalXml: `<Test xmlns:u="` + WsuNamespace + `"/>`,
+			expectedXml: `<Test xmlns:u="` + WsuNamespace + `" u:Id="123"/>`,
+		},
+		{
+			newId:       "123",
+			originalXml: `<Test xmlns:wsu="urn:other"/>`,
+			expectedXml: `<Test xmlns:wsu="urn:other" xmlns:wsu1="` + WsuNamespace + `" wsu1:Id="123"/>`,
+		},
 	}
 
 	for _, tc := range testCase {
@@ -88,3 +120,39 @@ func Test_SetWsuId(t *testing.T) {
 		}
 	}
 }
+
+func Test_GetWsuId_InheritedDeclaration(t *testing.T) {
+	// Prepare the test case
+	testCaseDocument := etree.NewDocument()
+	err := testCaseDocument.ReadFromString(`<Root xmlns:u="` + WsuNamespace + `"><Test u:Id="123"/></Root>`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCaseContext := xml.NewContext(testCaseDocument)
+	testCaseContext.SetNamespacePrefix("wsu", WsuNamespace)
+
+	// Get test case wsu:Id
+	wsuId := GetWsuId(testCaseContext, testCaseDocument.FindElement("//Test"))
+	if wsuId != "123" {
+		t.Fatalf("GetWsuId() = %s; want 123", wsuId)
+	}
+}
+
+func Test_SetWsuId_DetachedElement(t *testing.T) {
+	// Prepare the test case
+	testCaseContext := xml.NewContext(etree.NewDocument())
+	testCaseContext.SetNamespacePrefix("wsu", WsuNamespace)
+	el := etree.NewElement("Test")
+
+	// Set test case wsu:Id
+	SetWsuId(testCaseContext, el, "123")
+	if el.SelectAttrValue("wsu:Id", "") != "123" {
+		t.Fatal("SetWsuId() did not set wsu:Id on a detached element")
+	}
+	if el.SelectAttrValue("xmlns:wsu", "") != WsuNamespace {
+		t.Fatal("SetWsuId() did not declare the namespace on a detached element")
+	}
+	if GetWsuId(testCaseContext, el) != "123" {
+		t.Fatal("GetWsuId() did not resolve the context prefix on a detached element")
+	}
+}
@@ -2,10 +2,33 @@ package xmlsecurity
 
 import (
 	"crypto/x509"
+	"errors"
 
 	"github.com/deb-ict/go-xml"
 )
 
+var (
+	ErrX509CertificateNotAvailable = errors.New("x509 certificate not available")
+)
+
 type X509CertificateProvider interface {
 	GetX509Certificate(context xml.Context) (*x509.Certificate, error)
 }
+
+type X509ChainProvider interface {
+	GetX509CertificateChain(context xml.Context) ([]*x509.Certificate, error)
+}
+
+func getX509CertificateChain(context xml.Context, node xml.Node) ([]*x509.Certificate, error) {
+	if chainProvider, ok := node.(X509ChainProvider); ok {
+		return chainProvider.GetX509CertificateChain(context)
+	}
+	if provider, ok := node.(X509CertificateProvider); ok {
+		certificate, err := provider.GetX509Certificate(context)
+		if err != nil {
+			return nil, err
+		}
+		return []*x509.Certificate{certificate}, nil
+	}
+	return nil, ErrX509CertificateNotAvailable
+}
@@ -0,0 +1,97 @@
+package xmlsecurity
+
+import (
+	"crypto/x509"
+	"fmt"
+	"testing"
+
+	"github.com/beevik/etree"
+	"github.com/deb-ict/go-xml"
+)
+
+func Test_SecurityTokenReference_GetX509CertificateChain(t *testing.T) {
+	root := newTestCertificate(t, "Test Root", nil, nil)
+	leaf := newTestCertificate(t, "Test Leaf", root, nil)
+
+	// Create the test case BinarySecurityToken
+	testCaseBinarySecurityToken, err := NewX509ChainBinarySecurityToken(nil, []*x509.Certificate{leaf.Certificate, root.Certificate})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Create test case XML
+	testCaseXml := fmt.Sprintf(
+		`<wsse:Security xmlns:wsse="%s" xmlns:wsu="%s">`+
+			`<wsse:BinarySecurityToken EncodingType="%s" ValueType="%s" wsu:Id="BST-1">%s</wsse:BinarySecurityToken>`+
+			`<wsse:SecurityTokenReference><wsse:Reference URI="#BST-1"/></wsse:SecurityTokenReference>`+
+			`</wsse:Security>`,
+		WsseNamespace,
+		WsuNamespace,
+		testCaseBinarySecurityToken.GetEncodingType(),
+		testCaseBinarySecurityToken.GetValueType(),
+		testCaseBinarySecurityToken.GetValue(),
+	)
+
+	// Prepare the test case
+	testCaseDocument := etree.NewDocument()
+	err = testCaseDocument.ReadFromString(testCaseXml)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCaseContext := xml.NewContext(testCaseDocument)
+	ConfigureContext(testCaseContext)
+
+	// Load the test case SecurityTokenReference
+	testCaseSecurityTokenReference, err := NewSecurityTokenReference(testCaseContext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = testCaseSecurityTokenReference.LoadXml(testCaseContext, testCaseDocument.FindElement("//SecurityTokenReference"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Validate the forwarded chain
+	chain, err := testCaseSecurityTokenReference.GetX509CertificateChain(testCaseContext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(chain) != 2 || !chain[0].Equal(leaf.Certificate) || !chain[1].Equal(root.Certificate) {
+		t.Fatal("SecurityTokenReference.GetX509CertificateChain() did not return the token chain")
+	}
+	certificate, err := testCaseSecurityTokenReference.GetX509Certificate(testCaseContext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !certificate.Equal(leaf.Certificate) {
+		t.Fatal("SecurityTokenReference.GetX509Certificate() did not return the leaf certificate")
+	}
+}
+
+func Test_SecurityTokenReference_GetX509CertificateChain_KeyIdentifier(t *testing.T) {
+	certificate := newTestCertificate(t, "Test", nil, nil).Certificate
+
+	// Create the test case SecurityTokenReference
+	testCaseKeyIdentifier, err := NewX509KeyIdentifier(nil, certificate, X509v3ValueType)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCaseSecurityTokenReference, err := NewSecurityTokenReference(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = testCaseSecurityTokenReference.GetX509CertificateChain(nil)
+	if err != ErrX509CertificateNotAvailable {
+		t.Fatalf("SecurityTokenReference.GetX509CertificateChain() = %v; want %v", err, ErrX509CertificateNotAvailable)
+	}
+
+	testCaseSecurityTokenReference.SetContent(testCaseKeyIdentifier)
+	chain, err := testCaseSecurityTokenReference.GetX509CertificateChain(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(chain) != 1 || !chain[0].Equal(certificate) {
+		t.Fatal("SecurityTokenReference.GetX509CertificateChain() did not return the key identifier certificate")
+	}
+}
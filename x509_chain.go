@@ -0,0 +1,130 @@
+package xmlsecurity
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/asn1"
+	"errors"
+)
+
+var (
+	oidPkcs7SignedData = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 2}
+)
+
+type pkcs7ContentInfo struct {
+	ContentType asn1.ObjectIdentifier
+	Content     asn1.RawValue `asn1:"optional,tag:0"`
+}
+
+type pkcs7SignedData struct {
+	Version          int
+	DigestAlgorithms asn1.RawValue
+	ContentInfo      asn1.RawValue
+	Certificates     asn1.RawValue `asn1:"optional,tag:0"`
+	Crls             asn1.RawValue `asn1:"optional,tag:1"`
+	SignerInfos      asn1.RawValue
+}
+
+func parsePkiPath(data []byte) ([]*x509.Certificate, error) {
+	var path []asn1.RawValue
+	rest, err := asn1.Unmarshal(data, &path)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) > 0 {
+		return nil, errors.New("trailing data after PkiPath")
+	}
+	if len(path) == 0 {
+		return nil, ErrCertificateChainEmpty
+	}
+
+	// A PkiPath is ordered from the trust anchor to the end entity
+	chain := make([]*x509.Certificate, len(path))
+	for i, item := range path {
+		certificate, err := x509.ParseCertificate(item.FullBytes)
+		if err != nil {
+			return nil, err
+		}
+		chain[len(path)-1-i] = certificate
+	}
+
+	return chain, nil
+}
+
+func parsePkcs7Certificates(data []byte) ([]*x509.Certificate, error) {
+	var contentInfo pkcs7ContentInfo
+	rest, err := asn1.Unmarshal(data, &contentInfo)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) > 0 {
+		return nil, errors.New("trailing data after PKCS7 content info")
+	}
+	if !contentInfo.ContentType.Equal(oidPkcs7SignedData) {
+		return nil, errors.New("PKCS7 content is not signed data")
+	}
+
+	var signedData pkcs7SignedData
+	_, err = asn1.Unmarshal(contentInfo.Content.Bytes, &signedData)
+	if err != nil {
+		return nil, err
+	}
+	if len(signedData.Certificates.Bytes) == 0 {
+		return nil, ErrCertificateChainEmpty
+	}
+	certificates, err := x509.ParseCertificates(signedData.Certificates.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	// The certificates in a PKCS7 structure are an unordered set
+	leaf := findLeafCertificate(certificates)
+	return orderCertificateChain(leaf, certificates), nil
+}
+
+func findLeafCertificate(certificates []*x509.Certificate) *x509.Certificate {
+	for _, certificate := range certificates {
+		isIssuer := false
+		for _, other := range certificates {
+			if other != certificate && bytes.Equal(other.RawIssuer, certificate.RawSubject) && !bytes.Equal(other.RawIssuer, other.RawSubject) {
+				isIssuer = true
+				break
+			}
+		}
+		if !isIssuer {
+			return certificate
+		}
+	}
+	return certificates[0]
+}
+
+func orderCertificateChain(leaf *x509.Certificate, certificates []*x509.Certificate) []*x509.Certificate {
+	chain := []*x509.Certificate{leaf}
+	remaining := make([]*x509.Certificate, 0, len(certificates))
+	for _, certificate := range certificates {
+		if certificate != nil && !certificate.Equal(leaf) {
+			remaining = append(remaining, certificate)
+		}
+	}
+
+	// Follow the issuer links from the leaf, then keep whatever is left in its original order
+	current := leaf
+	for len(remaining) > 0 && !bytes.Equal(current.RawIssuer, current.RawSubject) {
+		index := -1
+		for i, certificate := range remaining {
+			if bytes.Equal(current.RawIssuer, certificate.RawSubject) && current.CheckSignatureFrom(certificate) == nil {
+				index = i
+				break
+			}
+		}
+		if index < 0 {
+			break
+		}
+
+		current = remaining[index]
+		chain = append(chain, current)
+		remaining = append(remaining[:index], remaining[index+1:]...)
+	}
+
+	return append(chain, remaining...)
+}
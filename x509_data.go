@@ -0,0 +1,205 @@
+package xmlsecurity
+
+import (
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"math/big"
+	"strings"
+
+	"github.com/beevik/etree"
+	"github.com/deb-ict/go-xml"
+)
+
+var (
+	ErrInvalidSerialNumber = errors.New("invalid serial number")
+)
+
+type X509Data interface {
+	xml.Node
+	X509CertificateProvider
+	GetIssuerName() string
+	SetIssuerName(issuerName string)
+	GetSerialNumber() *big.Int
+	SetSerialNumber(serialNumber *big.Int)
+	GetSubjectName() string
+	SetSubjectName(subjectName string)
+	GetCertificates() []*x509.Certificate
+	SetCertificates(certificates []*x509.Certificate)
+}
+
+type x509Data struct {
+	IssuerName   string
+	SerialNumber *big.Int
+	SubjectName  string
+	Certificates []*x509.Certificate
+}
+
+func NewX509Data(context xml.Context) (X509Data, error) {
+	return &x509Data{}, nil
+}
+
+func NewX509DataNode(context xml.Context) (xml.Node, error) {
+	return NewX509Data(context)
+}
+
+func NewX509IssuerSerialData(context xml.Context, cert *x509.Certificate) (X509Data, error) {
+	if cert == nil {
+		return nil, errors.New("certificate is nil")
+	}
+
+	return &x509Data{
+		IssuerName:   cert.Issuer.String(),
+		SerialNumber: cert.SerialNumber,
+	}, nil
+}
+
+func (node *x509Data) GetIssuerName() string {
+	return node.IssuerName
+}
+
+func (node *x509Data) SetIssuerName(issuerName string) {
+	node.IssuerName = issuerName
+}
+
+func (node *x509Data) GetSerialNumber() *big.Int {
+	return node.SerialNumber
+}
+
+func (node *x509Data) SetSerialNumber(serialNumber *big.Int) {
+	node.SerialNumber = serialNumber
+}
+
+func (node *x509Data) GetSubjectName() string {
+	return node.SubjectName
+}
+
+func (node *x509Data) SetSubjectName(subjectName string) {
+	node.SubjectName = subjectName
+}
+
+func (node *x509Data) GetCertificates() []*x509.Certificate {
+	return node.Certificates
+}
+
+func (node *x509Data) SetCertificates(certificates []*x509.Certificate) {
+	node.Certificates = certificates
+}
+
+func (node *x509Data) GetX509Certificate(context xml.Context) (*x509.Certificate, error) {
+	// Embedded certificates take precedence over a store lookup
+	if len(node.GetCertificates()) > 0 {
+		return node.GetCertificates()[0], nil
+	}
+
+	store := GetCertificateStore(context)
+	switch {
+	case node.GetSerialNumber() != nil:
+		if store == nil {
+			return nil, ErrX509CertificateNotAvailable
+		}
+		return store.FindByIssuerSerial(node.GetIssuerName(), node.GetSerialNumber())
+	case node.GetSubjectName() != "":
+		if store == nil {
+			return nil, ErrX509CertificateNotAvailable
+		}
+		certificates, err := store.FindBySubject(node.GetSubjectName())
+		if err != nil {
+			return nil, err
+		}
+		if len(certificates) != 1 {
+			return nil, ErrCertificateAmbiguous
+		}
+		return certificates[0], nil
+	default:
+		return nil, ErrX509CertificateNotAvailable
+	}
+}
+
+func (node *x509Data) LoadXml(context xml.Context, el *etree.Element) error {
+	err := xml.ValidateElement(el, "X509Data", DsigNamespace)
+	if err != nil {
+		return err
+	}
+
+	node.SetIssuerName("")
+	node.SetSerialNumber(nil)
+	node.SetSubjectName("")
+	node.SetCertificates(nil)
+
+	issuerSerialEl, err := xml.GetOptionalSingleChildElement(el, "X509IssuerSerial", DsigNamespace)
+	if err != nil {
+		return err
+	}
+	if issuerSerialEl != nil {
+		issuerNameEl, err := xml.GetSingleChildElement(issuerSerialEl, "X509IssuerName", DsigNamespace)
+		if err != nil {
+			return err
+		}
+		serialNumberEl, err := xml.GetSingleChildElement(issuerSerialEl, "X509SerialNumber", DsigNamespace)
+		if err != nil {
+			return err
+		}
+		serialNumber, ok := new(big.Int).SetString(strings.TrimSpace(serialNumberEl.Text()), 10)
+		if !ok {
+			return ErrInvalidSerialNumber
+		}
+		node.SetIssuerName(strings.TrimSpace(issuerNameEl.Text()))
+		node.SetSerialNumber(serialNumber)
+	}
+
+	subjectNameEl, err := xml.GetOptionalSingleChildElement(el, "X509SubjectName", DsigNamespace)
+	if err != nil {
+		return err
+	}
+	if subjectNameEl != nil {
+		node.SetSubjectName(strings.TrimSpace(subjectNameEl.Text()))
+	}
+
+	certificates := make([]*x509.Certificate, 0)
+	for _, child := range el.ChildElements() {
+		if !isElement(context, child, "X509Certificate", DsigNamespace) {
+			continue
+		}
+		data, err := decodeBase64Text(child.Text())
+		if err != nil {
+			return err
+		}
+		certificate, err := x509.ParseCertificate(data)
+		if err != nil {
+			return err
+		}
+		certificates = append(certificates, certificate)
+	}
+	node.SetCertificates(certificates)
+
+	return nil
+}
+
+func (node *x509Data) GetXml(context xml.Context) (*etree.Element, error) {
+	el := etree.NewElement("X509Data")
+	el.Space = context.GetNamespacePrefix(DsigNamespace)
+
+	if node.GetSerialNumber() != nil {
+		issuerSerialEl := el.CreateElement("X509IssuerSerial")
+		issuerSerialEl.Space = context.GetNamespacePrefix(DsigNamespace)
+		issuerNameEl := issuerSerialEl.CreateElement("X509IssuerName")
+		issuerNameEl.Space = context.GetNamespacePrefix(DsigNamespace)
+		issuerNameEl.SetText(node.GetIssuerName())
+		serialNumberEl := issuerSerialEl.CreateElement("X509SerialNumber")
+		serialNumberEl.Space = context.GetNamespacePrefix(DsigNamespace)
+		serialNumberEl.SetText(node.GetSerialNumber().String())
+	}
+	if node.GetSubjectName() != "" {
+		subjectNameEl := el.CreateElement("X509SubjectName")
+		subjectNameEl.Space = context.GetNamespacePrefix(DsigNamespace)
+		subjectNameEl.SetText(node.GetSubjectName())
+	}
+	for _, certificate := range node.GetCertificates() {
+		certificateEl := el.CreateElement("X509Certificate")
+		certificateEl.Space = context.GetNamespacePrefix(DsigNamespace)
+		certificateEl.SetText(base64.StdEncoding.EncodeToString(certificate.Raw))
+	}
+
+	return el, nil
+}
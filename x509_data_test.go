@@ -0,0 +1,144 @@
+package xmlsecurity
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/beevik/etree"
+	"github.com/deb-ict/go-xml"
+)
+
+func Test_SecurityTokenReference_GetX509Certificate_IssuerSerial(t *testing.T) {
+	root := newTestCertificate(t, "Test Root", nil, nil)
+	leaf := newTestCertificate(t, "Test Leaf", root, nil)
+
+	// Create test case XML
+	testCaseXml := fmt.Sprintf(
+		`<wsse:SecurityTokenReference xmlns:wsse="%s" xmlns:ds="%s">`+
+			`<ds:X509Data><ds:X509IssuerSerial>`+
+			`<ds:X509IssuerName>%s</ds:X509IssuerName>`+
+			`<ds:X509SerialNumber>%s</ds:X509SerialNumber>`+
+			`</ds:X509IssuerSerial></ds:X509Data>`+
+			`</wsse:SecurityTokenReference>`,
+		WsseNamespace,
+		DsigNamespace,
+		leaf.Certificate.Issuer.String(),
+		leaf.Certificate.SerialNumber.String(),
+	)
+
+	// Prepare the test case
+	testCaseDocument := etree.NewDocument()
+	err := testCaseDocument.ReadFromString(testCaseXml)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCaseContext := NewContext(xml.NewContext(testCaseDocument))
+	ConfigureContext(testCaseContext)
+
+	// Load test case SecurityTokenReference
+	testCaseSecurityTokenReference, err := NewSecurityTokenReference(testCaseContext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = testCaseSecurityTokenReference.LoadXml(testCaseContext, testCaseDocument.Root())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := testCaseSecurityTokenReference.GetContent().(X509Data); !ok {
+		t.Fatalf("SecurityTokenReference.Content = %T; want X509Data", testCaseSecurityTokenReference.GetContent())
+	}
+
+	// Resolve without a certificate store
+	_, err = testCaseSecurityTokenReference.GetX509Certificate(testCaseContext)
+	if err != ErrX509CertificateNotAvailable {
+		t.Fatalf("SecurityTokenReference.GetX509Certificate() error = %v; want %v", err, ErrX509CertificateNotAvailable)
+	}
+
+	// Resolve through the certificate store
+	testCaseContext.SetCertificateStore(NewMemoryCertificateStore(root.Certificate))
+	_, err = testCaseSecurityTokenReference.GetX509Certificate(testCaseContext)
+	if err != ErrCertificateNotFound {
+		t.Fatalf("SecurityTokenReference.GetX509Certificate() error = %v; want %v", err, ErrCertificateNotFound)
+	}
+	testCaseContext.SetCertificateStore(NewMemoryCertificateStore(root.Certificate, leaf.Certificate))
+	certificate, err := testCaseSecurityTokenReference.GetX509Certificate(testCaseContext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !certificate.Equal(leaf.Certificate) {
+		t.Fatal("SecurityTokenReference.GetX509Certificate() did not return the leaf certificate")
+	}
+}
+
+func Test_X509Data_GetX509Certificate_SubjectName(t *testing.T) {
+	root := newTestCertificate(t, "Test Root", nil, nil)
+	leaf := newTestCertificate(t, "Test Leaf", root, nil)
+	other := newTestCertificate(t, "Test Leaf", root, nil)
+
+	// Prepare the test case
+	testCaseContext := NewContext(xml.NewContext(etree.NewDocument()))
+	testCaseContext.SetCertificateStore(NewMemoryCertificateStore(root.Certificate, leaf.Certificate))
+
+	// Create test case X509Data
+	testCaseX509Data, err := NewX509Data(testCaseContext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCaseX509Data.SetSubjectName(leaf.Certificate.Subject.String())
+
+	// Resolve test case X509Data
+	certificate, err := testCaseX509Data.GetX509Certificate(testCaseContext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !certificate.Equal(leaf.Certificate) {
+		t.Fatal("X509Data.GetX509Certificate() did not return the leaf certificate")
+	}
+
+	// Resolve test case X509Data with several matching certificates
+	testCaseContext.SetCertificateStore(NewMemoryCertificateStore(leaf.Certificate, other.Certificate))
+	_, err = testCaseX509Data.GetX509Certificate(testCaseContext)
+	if err != ErrCertificateAmbiguous {
+		t.Fatalf("X509Data.GetX509Certificate() error = %v; want %v", err, ErrCertificateAmbiguous)
+	}
+}
+
+func Test_X509Data_GetXml(t *testing.T) {
+	root := newTestCertificate(t, "Test Root", nil, nil)
+	leaf := newTestCertificate(t, "Test Leaf", root, nil)
+
+	// Prepare the test case
+	testCaseDocument := etree.NewDocument()
+	testCaseContext := xml.NewContext(testCaseDocument)
+	ConfigureContext(testCaseContext)
+
+	// Create test case X509Data
+	testCaseX509Data, err := NewX509IssuerSerialData(testCaseContext, leaf.Certificate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCaseX509DataElement, err := testCaseX509Data.GetXml(testCaseContext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCaseX509DataElement.CreateAttr("xmlns:ds", DsigNamespace)
+	testCaseDocument.SetRoot(testCaseX509DataElement)
+
+	// Reload the test case X509Data
+	loadedX509Data, err := NewX509Data(testCaseContext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = loadedX509Data.LoadXml(testCaseContext, testCaseDocument.Root())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Validate the loaded X509Data
+	if loadedX509Data.GetIssuerName() != leaf.Certificate.Issuer.String() {
+		t.Fatalf("X509Data.IssuerName = %s; want %s", loadedX509Data.GetIssuerName(), leaf.Certificate.Issuer.String())
+	}
+	if loadedX509Data.GetSerialNumber().Cmp(leaf.Certificate.SerialNumber) != 0 {
+		t.Fatalf("X509Data.SerialNumber = %s; want %s", loadedX509Data.GetSerialNumber(), leaf.Certificate.SerialNumber)
+	}
+}
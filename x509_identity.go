@@ -0,0 +1,96 @@
+package xmlsecurity
+
+import (
+	"crypto"
+	"crypto/x509"
+	"errors"
+	"os"
+
+	"github.com/deb-ict/go-xml"
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+var (
+	ErrInvalidPrivateKey     = errors.New("private key is not a crypto.Signer")
+	ErrPrivateKeyMismatch    = errors.New("private key does not match certificate")
+	ErrCertificateChainEmpty = errors.New("certificate chain is empty")
+)
+
+type X509Identity interface {
+	GetCertificate() *x509.Certificate
+	GetCertificateChain() []*x509.Certificate
+	GetSigner() crypto.Signer
+	GetDecrypter() crypto.Decrypter
+	GetBinarySecurityToken(context xml.Context) (BinarySecurityToken, error)
+}
+
+type x509Identity struct {
+	chain      []*x509.Certificate
+	privateKey crypto.Signer
+}
+
+type publicKeyComparer interface {
+	Equal(x crypto.PublicKey) bool
+}
+
+func NewX509Identity(privateKey crypto.PrivateKey, chain ...*x509.Certificate) (X509Identity, error) {
+	if len(chain) == 0 {
+		return nil, ErrCertificateChainEmpty
+	}
+	signer, ok := privateKey.(crypto.Signer)
+	if !ok {
+		return nil, ErrInvalidPrivateKey
+	}
+	publicKey, ok := signer.Public().(publicKeyComparer)
+	if !ok || !publicKey.Equal(chain[0].PublicKey) {
+		return nil, ErrPrivateKeyMismatch
+	}
+
+	return &x509Identity{
+		chain:      orderCertificateChain(chain[0], chain[1:]),
+		privateKey: signer,
+	}, nil
+}
+
+func LoadPkcs12Identity(data []byte, password string) (X509Identity, error) {
+	privateKey, certificate, caCertificates, err := pkcs12.DecodeChain(data, password)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewX509Identity(privateKey, append([]*x509.Certificate{certificate}, caCertificates...)...)
+}
+
+func LoadPkcs12IdentityFromFile(name string, password string) (X509Identity, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	return LoadPkcs12Identity(data, password)
+}
+
+func (identity *x509Identity) GetCertificate() *x509.Certificate {
+	return identity.chain[0]
+}
+
+func (identity *x509Identity) GetCertificateChain() []*x509.Certificate {
+	result := make([]*x509.Certificate, len(identity.chain))
+	copy(result, identity.chain)
+	return result
+}
+
+func (identity *x509Identity) GetSigner() crypto.Signer {
+	return identity.privateKey
+}
+
+func (identity *x509Identity) GetDecrypter() crypto.Decrypter {
+	decrypter, ok := identity.privateKey.(crypto.Decrypter)
+	if !ok {
+		return nil
+	}
+	return decrypter
+}
+
+func (identity *x509Identity) GetBinarySecurityToken(context xml.Context) (BinarySecurityToken, error) {
+	return NewX509BinarySecurityToken(context, identity.GetCertificate())
+}
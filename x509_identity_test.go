@@ -0,0 +1,145 @@
+package xmlsecurity
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/beevik/etree"
+	"github.com/deb-ict/go-xml"
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+func Test_LoadPkcs12Identity(t *testing.T) {
+	root := newTestCertificate(t, "Test Root", nil, nil)
+	intermediate := newTestCertificate(t, "Test Intermediate", root, func(template *x509.Certificate) {
+		template.IsCA = true
+		template.KeyUsage |= x509.KeyUsageCertSign
+	})
+	leaf := newTestCertificate(t, "Test Leaf", intermediate, nil)
+
+	// Create the test case PKCS#12 bundle
+	pfxData, err := pkcs12.Modern.Encode(leaf.PrivateKey, leaf.Certificate, []*x509.Certificate{root.Certificate, intermediate.Certificate}, "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Load the test case identity
+	identity, err := LoadPkcs12Identity(pfxData, "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Validate the test case identity
+	if !identity.GetCertificate().Equal(leaf.Certificate) {
+		t.Fatal("X509Identity.GetCertificate() did not return the leaf certificate")
+	}
+	chain := identity.GetCertificateChain()
+	if len(chain) != 3 || !chain[1].Equal(intermediate.Certificate) || !chain[2].Equal(root.Certificate) {
+		t.Fatal("X509Identity.GetCertificateChain() is not ordered leaf to root")
+	}
+	if identity.GetSigner() == nil {
+		t.Fatal("X509Identity.GetSigner() = nil")
+	}
+	if identity.GetDecrypter() != nil {
+		t.Fatal("X509Identity.GetDecrypter() = not nil for an ECDSA key")
+	}
+
+	// Load with an invalid password
+	_, err = LoadPkcs12Identity(pfxData, "wrong")
+	if err == nil {
+		t.Fatal("LoadPkcs12Identity() with an invalid password succeeded")
+	}
+}
+
+func Test_LoadPkcs12IdentityFromFile_Rsa(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "Test RSA"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, privateKey.Public(), privateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certificate, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Prepare the test case file
+	pfxData, err := pkcs12.Modern.Encode(privateKey, certificate, nil, "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	name := filepath.Join(t.TempDir(), "identity.p12")
+	err = os.WriteFile(name, pfxData, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Load the test case identity
+	identity, err := LoadPkcs12IdentityFromFile(name, "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if identity.GetDecrypter() == nil {
+		t.Fatal("X509Identity.GetDecrypter() = nil for an RSA key")
+	}
+}
+
+func Test_NewX509Identity_PrivateKeyMismatch(t *testing.T) {
+	first := newTestCertificate(t, "First", nil, nil)
+	second := newTestCertificate(t, "Second", nil, nil)
+
+	_, err := NewX509Identity(first.PrivateKey, second.Certificate)
+	if err != ErrPrivateKeyMismatch {
+		t.Fatalf("NewX509Identity() = %v; want %v", err, ErrPrivateKeyMismatch)
+	}
+	_, err = NewX509Identity(first.PrivateKey)
+	if err != ErrCertificateChainEmpty {
+		t.Fatalf("NewX509Identity() = %v; want %v", err, ErrCertificateChainEmpty)
+	}
+}
+
+func Test_X509Identity_GetBinarySecurityToken(t *testing.T) {
+	leaf := newTestCertificate(t, "Test Leaf", nil, nil)
+	identity, err := NewX509Identity(leaf.PrivateKey, leaf.Certificate)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Prepare the test case
+	testCaseContext := xml.NewContext(etree.NewDocument())
+
+	// Get the test case BinarySecurityToken
+	token, err := identity.GetBinarySecurityToken(testCaseContext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token.GetId() == "" {
+		t.Fatal("BinarySecurityToken.Id is empty")
+	}
+	if token.GetValue() != base64.StdEncoding.EncodeToString(leaf.Certificate.Raw) {
+		t.Fatal("BinarySecurityToken.Value does not contain the leaf certificate")
+	}
+	certificate, err := token.GetX509Certificate(testCaseContext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !certificate.Equal(leaf.Certificate) {
+		t.Fatal("BinarySecurityToken.GetX509Certificate() returned a different certificate")
+	}
+}
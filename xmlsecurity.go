@@ -3,17 +3,43 @@ package xmlsecurity
 import "github.com/deb-ict/go-xml"
 
 const (
-	WsuNamespace    string = "http://docs.oasis-open.org/wss/2004/01/oasis-200401-wss-wssecurity-utility-1.0.xsd"
-	WsseNamespace   string = "http://docs.oasis-open.org/wss/2004/01/oasis-200401-wss-wssecurity-secext-1.0.xsd"
-	Wsse11Namespace string = "http://docs.oasis-open.org/wss/oasis-wss-wssecurity-secext-1.1.xsd"
+	WsuNamespace     string = "http://docs.oasis-open.org/wss/2004/01/oasis-200401-wss-wssecurity-utility-1.0.xsd"
+	WsseNamespace    string = "http://docs.oasis-open.org/wss/2004/01/oasis-200401-wss-wssecurity-secext-1.0.xsd"
+	Wsse11Namespace  string = "http://docs.oasis-open.org/wss/oasis-wss-wssecurity-secext-1.1.xsd"
+	Soap11Namespace  string = "http://schemas.xmlsoap.org/soap/envelope/"
+	Soap12Namespace  string = "http://www.w3.org/2003/05/soap-envelope"
+	DsigNamespace    string = "http://www.w3.org/2000/09/xmldsig#"
+	ExcC14NNamespace string = "http://www.w3.org/2001/10/xml-exc-c14n#"
+)
+
+const (
+	Base64BinaryEncodingType string = "http://docs.oasis-open.org/wss/2004/01/oasis-200401-wss-soap-message-security-1.0#Base64Binary"
 )
 
 func ConfigureContext(context xml.Context) {
 	context.SetNamespacePrefix("wsu", WsuNamespace)
 	context.SetNamespacePrefix("wsse", WsseNamespace)
 	context.SetNamespacePrefix("wsse11", Wsse11Namespace)
+	context.SetNamespacePrefix("soap", Soap11Namespace)
+	context.SetNamespacePrefix("soap12", Soap12Namespace)
+	context.SetNamespacePrefix("ds", DsigNamespace)
+	context.SetNamespacePrefix("ec", ExcC14NNamespace)
 
+	context.RegisterTypeConstructor(WsseNamespace, "Security", NewSecurityNode)
 	context.RegisterTypeConstructor(WsseNamespace, "BinarySecurityToken", NewBinarySecurityTokenNode)
 	context.RegisterTypeConstructor(WsseNamespace, "SecurityTokenReference", NewSecurityTokenReferenceNode)
 	context.RegisterTypeConstructor(WsseNamespace, "Reference", NewReferenceNode)
+	context.RegisterTypeConstructor(WsseNamespace, "KeyIdentifier", NewKeyIdentifierNode)
+	context.RegisterTypeConstructor(WsseNamespace, "UsernameToken", NewUsernameTokenNode)
+	context.RegisterTypeConstructor(WsuNamespace, "Timestamp", NewTimestampNode)
+	context.RegisterTypeConstructor(DsigNamespace, "Signature", NewSignatureNode)
+	context.RegisterTypeConstructor(DsigNamespace, "SignedInfo", NewSignedInfoNode)
+	context.RegisterTypeConstructor(DsigNamespace, "CanonicalizationMethod", NewCanonicalizationMethodNode)
+	context.RegisterTypeConstructor(DsigNamespace, "SignatureMethod", NewSignatureMethodNode)
+	context.RegisterTypeConstructor(DsigNamespace, "Reference", NewSignatureReferenceNode)
+	context.RegisterTypeConstructor(DsigNamespace, "Transform", NewTransformNode)
+	context.RegisterTypeConstructor(DsigNamespace, "DigestMethod", NewDigestMethodNode)
+	context.RegisterTypeConstructor(DsigNamespace, "SignatureValue", NewSignatureValueNode)
+	context.RegisterTypeConstructor(DsigNamespace, "KeyInfo", NewKeyInfoNode)
+	context.RegisterTypeConstructor(DsigNamespace, "X509Data", NewX509DataNode)
 }